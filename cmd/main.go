@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/gofiber/fiber/v2"
 	//"github.com/gofiber/fiber/v2/middleware/logger"
@@ -10,11 +13,13 @@ import (
 	"time"
     fiberSwagger "github.com/swaggo/fiber-swagger"
     _ "github.com/lokesh2201013/postgres-data-summary/docs" 
+	"github.com/lokesh2201013/postgres-data-summary/internal/config"
 	"github.com/lokesh2201013/postgres-data-summary/internal/handler"
 	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
 	"github.com/lokesh2201013/postgres-data-summary/internal/repository/external"
 	"github.com/lokesh2201013/postgres-data-summary/internal/repository/local"
 	"github.com/lokesh2201013/postgres-data-summary/internal/router"
+	"github.com/lokesh2201013/postgres-data-summary/internal/secrets"
 	"github.com/lokesh2201013/postgres-data-summary/internal/service"
 )
 
@@ -22,28 +27,70 @@ func main() {
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found")
 	}
-    time.Sleep(5*time.Second)
-	local.ConnectDB()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	time.Sleep(cfg.Server.StartupDelay)
+	local.ConnectDB(cfg.Database)
 
 	repo := local.NewSummaryRepository()
 
-	client := external.NewSummaryClient()
-	summarySvc := service.NewSummaryService(repo, client,1,2*time.Second)
-	h := handler.NewSummaryHandler(summarySvc)
+	client, err := external.NewSummaryClient(external.ClientConfigFrom(cfg.External))
+	if err != nil {
+		log.Fatalf("External service client: %v", err)
+	}
+	breaker := external.NewBreaker(client, external.BreakerConfigFrom(cfg.External))
+	var cipher secrets.Cipher
+	keyring, err := cfg.Secrets.Keyring()
+	if err != nil {
+		log.Fatalf("SECRET_KEYS: %v", err)
+	}
+	if keyring != nil {
+		cipher = keyring
+	} else {
+		log.Println("SECRET_KEYS not set, sources and schedules cannot store passwords")
+	}
+	sourceSvc := service.NewSourceService(local.NewSourceRepository(), cipher)
+
+	summarySvc := service.NewSummaryService(repo, breaker, cfg.Sync.RetryPolicy())
+	jobSvc := service.NewJobService(local.NewJobRepository(), summarySvc, sourceSvc, cfg.Sync.Workers, cfg.Sync.QueueSize)
+	h := handler.NewSummaryHandler(summarySvc, jobSvc)
+	scheduleSvc := service.NewScheduleService(local.NewScheduleRepository(), jobSvc, sourceSvc, cipher)
 
 	app := fiber.New()
     logger.InitLogger()
 	//app.Use(logger.New())
     app.Use(logger.ZapLogger())
+	// Cancelled on SIGINT/SIGTERM: stops the workers, scheduler and
+	// retention loop, aborts in-flight fetches and shuts the server down.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	jobSvc.Start(ctx)
+	scheduleSvc.Start(ctx, cfg.Scheduler.Tick)
+	if cfg.Retention.Days > 0 {
+		summarySvc.StartRetention(ctx, cfg.Retention.Period(), cfg.Retention.Tick)
+	}
+	go func() {
+		<-ctx.Done()
+		log.Println("Shutting down")
+		if err := app.Shutdown(); err != nil {
+			log.Printf("server shutdown error: %v", err)
+		}
+	}()
 	router.SummaryRoutes(app, h)
+	router.ScheduleRoutes(app, handler.NewScheduleHandler(scheduleSvc))
+	router.SourceRoutes(app, handler.NewSourceHandler(sourceSvc))
+	router.ServerRoutes(app, handler.NewServerHandler(service.NewServerService(local.NewServerRepository(), breaker, jobSvc)))
+	router.AdminRoutes(app, handler.NewAdminHandler(breaker))
+	router.SearchRoutes(app, handler.NewSearchHandler(service.NewSearchService(local.NewSearchRepository())))
+	router.StatsRoutes(app, handler.NewStatsHandler(service.NewStatsService(local.NewStatsRepository())))
+	router.TrendRoutes(app, handler.NewTrendHandler(service.NewTrendService(local.NewTrendRepository())))
      app.Get("/swagger/*", fiberSwagger.WrapHandler)
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = ":8080"
-	}
 
-	log.Printf("Server listening on %s", port)
-	if err := app.Listen(port); err != nil {
+	log.Printf("Server listening on %s", cfg.Server.Port)
+	if err := app.Listen(cfg.Server.Port); err != nil {
 		log.Fatalf("server error: %v", err)
 	}
 }
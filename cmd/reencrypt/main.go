@@ -0,0 +1,49 @@
+// Command reencrypt rewraps every stored connection password under the
+// active key of SECRET_KEYS. Run it after rotating keys: add the new key to
+// the front of SECRET_KEYS (or set SECRET_ACTIVE_KEY), run this command, and
+// then remove the retired key.
+package main
+
+import (
+	"log"
+
+	"github.com/joho/godotenv"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/config"
+	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
+	"github.com/lokesh2201013/postgres-data-summary/internal/repository/local"
+	"github.com/lokesh2201013/postgres-data-summary/internal/service"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+	logger.InitLogger()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	keyring, err := cfg.Secrets.Keyring()
+	if err != nil {
+		log.Fatalf("SECRET_KEYS: %v", err)
+	}
+	if keyring == nil {
+		log.Fatal("SECRET_KEYS is not set")
+	}
+
+	local.ConnectDB(cfg.Database)
+
+	sources, err := service.NewSourceService(local.NewSourceRepository(), keyring).RewrapPasswords()
+	if err != nil {
+		log.Fatalf("Rewrapping source passwords failed after %d: %v", sources, err)
+	}
+	schedules, err := service.NewScheduleService(local.NewScheduleRepository(), nil, nil, keyring).RewrapPasswords()
+	if err != nil {
+		log.Fatalf("Rewrapping schedule passwords failed after %d: %v", schedules, err)
+	}
+
+	log.Printf("Active key %q: rewrapped %d source and %d schedule passwords",
+		keyring.ActiveKeyID(), sources, schedules)
+}
@@ -15,37 +15,118 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/summary/summaries": {
+        "/summary/admin/circuits": {
+            "get": {
+                "description": "Reports the circuit breaker of every target synced since startup. Syncs of a target whose circuit is open fail immediately until retry_at.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List circuit breakers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.CircuitState"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/summary/admin/circuits/{target}/reset": {
+            "post": {
+                "description": "Closes the circuit of a target so the next sync contacts it immediately",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Close a circuit breaker",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Target as host:port",
+                        "name": "target",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/summary/diff": {
             "get": {
-                "description": "Retrieves paginated summaries",
+                "description": "Compares two stored summaries and reports added, removed and renamed schemas and tables, row count changes and size deltas",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "summary"
                 ],
-                "summary": "Get all summaries",
+                "summary": "Diff two summaries",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
+                        "type": "string",
+                        "description": "ID of the older summary",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Page size",
-                        "name": "pageSize",
-                        "in": "query"
+                        "type": "string",
+                        "description": "ID of the newer summary",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.Summary"
+                            "$ref": "#/definitions/domain.SummaryDiff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -61,20 +142,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/summary/summaries/{id}": {
+        "/summary/jobs/{id}": {
             "get": {
-                "description": "Retrieves full summary by ID",
+                "description": "Retrieves the status, attempt count, timings and resulting summary ID of a sync job",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "summary"
                 ],
-                "summary": "Get summary by ID",
+                "summary": "Get sync job status",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Summary ID",
+                        "description": "Job ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -84,7 +165,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Summary"
+                            "$ref": "#/definitions/domain.SyncJob"
                         }
                     },
                     "404": {
@@ -108,9 +189,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/summary/sync": {
+        "/summary/schedules": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "List sync schedules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Schedule"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Connects to remote PostgreSQL via external API and saves summary",
+                "description": "Registers a database to be synced on a cron expression (e.g. \"0 3 * * *\") or a fixed interval (e.g. \"6h\")",
                 "consumes": [
                     "application/json"
                 ],
@@ -118,17 +228,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "summary"
+                    "schedules"
                 ],
-                "summary": "Sync a new database summary",
+                "summary": "Create a recurring sync schedule",
                 "parameters": [
                     {
-                        "description": "Remote DB connection",
-                        "name": "details",
+                        "description": "Target and cron or interval",
+                        "name": "schedule",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.ConnectionDetails"
+                            "$ref": "#/definitions/domain.ScheduleRequest"
                         }
                     }
                 ],
@@ -136,7 +246,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/domain.Summary"
+                            "$ref": "#/definitions/domain.Schedule"
                         }
                     },
                     "400": {
@@ -159,82 +269,2518 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "domain.ConnectionDetails": {
-            "type": "object",
-            "properties": {
-                "dbname": {
-                    "type": "string"
-                },
-                "host": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string"
-                },
-                "port": {
-                    "type": "integer"
-                },
-                "user": {
-                    "type": "string"
-                }
-            }
         },
-        "domain.Schema": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "summary_id": {
-                    "type": "string"
-                },
-                "synced_at": {
-                    "type": "string"
-                },
-                "tables": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.Table"
+        "/summary/schedules/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Get a sync schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Schedule"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
-            }
-        },
-        "domain.Summary": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "string"
-                },
-                "schemas": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.Schema"
+            },
+            "delete": {
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Delete a sync schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
-                },
-                "source_info": {
-                    "$ref": "#/definitions/domain.ConnectionDetails"
-                },
-                "synced_at": {
-                    "type": "string"
                 }
             }
         },
-        "domain.Table": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "row_count": {
+        "/summary/schedules/{id}/pause": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Pause a sync schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Schedule"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/summary/schedules/{id}/resume": {
+            "post": {
+                "description": "Reactivates a schedule; runs missed while paused are skipped",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Resume a paused sync schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Schedule"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/summary/search": {
+            "get": {
+                "description": "Finds tables and schemas whose names are similar to or contain q across all stored summaries, with the summary, host and database of each match. Exact name matches rank first, then by trigram similarity. Only the newest snapshot of each source is searched unless history is set.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Search tables and schemas across databases",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Table or schema name to look for",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "table",
+                            "schema"
+                        ],
+                        "type": "string",
+                        "description": "Only match tables or only schemas",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also search older snapshots",
+                        "name": "history",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.SearchPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/summary/servers": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "servers"
+                ],
+                "summary": "List discovered servers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Server"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/summary/servers/discover": {
+            "post": {
+                "description": "Lists the databases of the server (excluding templates), groups their sources under a server record and queues a background sync of each with the given credentials. The response lists each database's job ID without waiting for the syncs. dbname is optional and only selects the database connected to for the listing (default postgres).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "servers"
+                ],
+                "summary": "Sync every database on a server",
+                "parameters": [
+                    {
+                        "description": "Server connection",
+                        "name": "details",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ConnectionDetails"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ServerDiscovery"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/summary/servers/{id}": {
+            "get": {
+                "description": "Returns the server with the sources discovered on it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "servers"
+                ],
+                "summary": "Get a server and its databases",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Server ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Server"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/summary/sources": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sources"
+                ],
+                "summary": "List connection profiles",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Source"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Stores host, port, user, dbname, sslmode and an encrypted password so syncs can reference the source by ID. A database already synced without a profile keeps its source ID and snapshot history; 409 is returned only when a profile is already saved for it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sources"
+                ],
+                "summary": "Save a connection profile",
+                "parameters": [
+                    {
+                        "description": "Connection profile",
+                        "name": "details",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ConnectionDetails"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Source"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/summary/sources/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sources"
+                ],
+                "summary": "Get a connection profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Source ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Source"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces the connection settings of a source; an empty password keeps the stored one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sources"
+                ],
+                "summary": "Update a connection profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Source ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Connection profile",
+                        "name": "details",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ConnectionDetails"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Source"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes the profile and its stored password; snapshots already taken are kept",
+                "tags": [
+                    "sources"
+                ],
+                "summary": "Delete a connection profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Source ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/summary/sources/{id}/snapshots": {
+            "get": {
+                "description": "Retrieves the paginated snapshot history of a source, newest first, without the schema tree",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "summary"
+                ],
+                "summary": "List snapshots of a source database",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Source ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Summary"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/summary/sources/{id}/snapshots/{snapshotId}": {
+            "get": {
+                "description": "Retrieves a full snapshot of a source database by snapshot ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "summary"
+                ],
+                "summary": "Get a historical snapshot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Source ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Snapshot (summary) ID",
+                        "name": "snapshotId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Summary"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/summary/sources/{id}/trends": {
+            "get": {
+                "description": "Computes row count and size growth of a source database and each table of its newest snapshot over each window, from its snapshot history. Rates are least-squares slopes per day. With thresholdMb, forecasts when the database reaches that size at the growth rate of the longest window.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trends"
+                ],
+                "summary": "Get growth trends of a source database",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Source ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated growth windows in days (default 7,30,90)",
+                        "name": "windows",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Database size to forecast against",
+                        "name": "thresholdMb",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.SourceTrends"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/summary/stats": {
+            "get": {
+                "description": "Aggregates the newest snapshot of every source: total databases, schemas, tables, rows and size, the largest tables and databases, and how many databases fall into each size bucket.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get fleet-wide statistics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of largest tables and databases to list (1-100)",
+                        "name": "top",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.FleetStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/summary/summaries": {
+            "get": {
+                "description": "Retrieves a page of summaries with their schema count, table count and total size, optionally filtered by target, sync time, table name or total size, searched by schema or table name, and sorted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "summary"
+                ],
+                "summary": "Get all summaries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only summaries of this host",
+                        "name": "host",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only summaries of this database",
+                        "name": "dbname",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only summaries synced at or after this RFC 3339 time",
+                        "name": "syncedAfter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only summaries synced before this RFC 3339 time",
+                        "name": "syncedBefore",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only summaries containing a table with this exact name",
+                        "name": "hasTable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only summaries whose tables total at least this many MB",
+                        "name": "minSizeMb",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Case-insensitive substring of a schema or table name",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "synced_at",
+                            "total_size",
+                            "table_count"
+                        ],
+                        "type": "string",
+                        "default": "synced_at",
+                        "description": "Sort key",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "full",
+                            "compact"
+                        ],
+                        "type": "string",
+                        "default": "full",
+                        "description": "compact leaves out the schemas and tables",
+                        "name": "view",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.SummaryPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/summary/summaries/{id}": {
+            "get": {
+                "description": "Retrieves full summary by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "summary"
+                ],
+                "summary": "Get summary by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Summary ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Summary"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Soft-deletes a summary so it no longer appears in listings, snapshots or diffs; it can be restored until it is purged. With purge=true the summary, deleted or not, is removed permanently together with its schemas, tables, columns, indexes and constraints.",
+                "tags": [
+                    "summary"
+                ],
+                "summary": "Delete a summary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Summary ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Remove permanently instead of soft-deleting",
+                        "name": "purge",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/summary/summaries/{id}/restore": {
+            "post": {
+                "description": "Undoes a soft delete. Purged summaries cannot be restored.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "summary"
+                ],
+                "summary": "Restore a deleted summary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Summary ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Summary"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/summary/summaries/{id}/schemas": {
+            "get": {
+                "description": "Retrieves a page of the schemas of a summary with their table count and total size, without loading any tables",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "summary"
+                ],
+                "summary": "List the schemas of a summary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Summary ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "name",
+                            "table_count",
+                            "total_size"
+                        ],
+                        "type": "string",
+                        "default": "name",
+                        "description": "Sort key",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Sort direction",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.SchemaPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/summary/summaries/{id}/schemas/{schema}/tables": {
+            "get": {
+                "description": "Retrieves a page of the tables of one schema of a summary, without their columns, indexes and constraints",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "summary"
+                ],
+                "summary": "List the tables of a schema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Summary ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schema name",
+                        "name": "schema",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "name",
+                            "row_count",
+                            "size_mb"
+                        ],
+                        "type": "string",
+                        "default": "name",
+                        "description": "Sort key",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Sort direction",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TablePage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/summary/summaries/{id}/schemas/{schema}/tables/{table}": {
+            "get": {
+                "description": "Retrieves one table with its columns, indexes and constraints",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "summary"
+                ],
+                "summary": "Get a table of a summary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Summary ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schema name",
+                        "name": "schema",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Table name",
+                        "name": "table",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Table"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/summary/sync": {
+            "post": {
+                "description": "Queues a background job that connects to remote PostgreSQL via external API and saves a summary. Send either the ID of a saved source or raw connection details. Poll the returned job for progress.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "summary"
+                ],
+                "summary": "Sync a new database summary",
+                "parameters": [
+                    {
+                        "description": "Saved source ID or remote DB connection",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.SyncRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/domain.SyncJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/summary/sync/batch": {
+            "post": {
+                "description": "Queues a background sync job for every item, each either a saved source ID or raw connection details, and returns without waiting for them. The response lists each item's job ID, or why it was rejected, in request order; poll GET /summary/jobs/{id} for progress. A rejected item does not fail the batch.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "summary"
+                ],
+                "summary": "Sync many databases in one call",
+                "parameters": [
+                    {
+                        "description": "Saved source IDs or remote DB connections",
+                        "name": "requests",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.SyncRequest"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/domain.BatchSyncResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "domain.BatchSyncResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "queued": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.BatchSyncResult"
+                    }
+                }
+            }
+        },
+        "domain.BatchSyncResult": {
+            "type": "object",
+            "properties": {
+                "dbname": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "source_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.CapacityForecast": {
+            "type": "object",
+            "properties": {
+                "crosses_at": {
+                    "type": "string"
+                },
+                "days_until": {
+                    "type": "number"
+                },
+                "reached": {
+                    "type": "boolean"
+                },
+                "size_mb_per_day": {
+                    "type": "number"
+                },
+                "threshold_mb": {
+                    "type": "number"
+                },
+                "window_days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.CircuitState": {
+            "type": "object",
+            "properties": {
+                "consecutive_failures": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "opened_at": {
+                    "type": "string"
+                },
+                "retry_at": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "target": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Column": {
+            "type": "object",
+            "properties": {
+                "collation": {
+                    "type": "string"
+                },
+                "data_type": {
+                    "type": "string"
+                },
+                "default_value": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_generated": {
+                    "type": "boolean"
+                },
+                "is_identity": {
+                    "type": "boolean"
+                },
+                "is_nullable": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ordinal_position": {
+                    "type": "integer"
+                },
+                "table_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ConnectionDetails": {
+            "type": "object",
+            "properties": {
+                "dbname": {
+                    "type": "string"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "sslmode": {
+                    "description": "SSLMode is the libpq sslmode used to connect, e.g. \"require\" or\n\"verify-full\".",
+                    "type": "string"
+                },
+                "user": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Constraint": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "definition": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "on_delete": {
+                    "type": "string"
+                },
+                "on_update": {
+                    "type": "string"
+                },
+                "referenced_columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "referenced_schema": {
+                    "type": "string"
+                },
+                "referenced_table": {
+                    "type": "string"
+                },
+                "table_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.DatabaseStat": {
+            "type": "object",
+            "properties": {
+                "dbname": {
+                    "type": "string"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "row_count": {
+                    "type": "integer"
+                },
+                "size_mb": {
+                    "type": "number"
+                },
+                "source_id": {
+                    "type": "string"
+                },
+                "summary_id": {
+                    "type": "string"
+                },
+                "synced_at": {
+                    "type": "string"
+                },
+                "table_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.DatabaseTrend": {
+            "type": "object",
+            "properties": {
+                "growth": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Growth"
+                    }
+                },
+                "row_count": {
+                    "type": "integer"
+                },
+                "size_mb": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.FleetStats": {
+            "type": "object",
+            "properties": {
+                "databases": {
+                    "type": "integer"
+                },
+                "largest_databases": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.DatabaseStat"
+                    }
+                },
+                "largest_tables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.TableStat"
+                    }
+                },
+                "row_count": {
+                    "type": "integer"
+                },
+                "schemas": {
+                    "type": "integer"
+                },
+                "size_distribution": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.SizeBucket"
+                    }
+                },
+                "size_mb": {
+                    "type": "number"
+                },
+                "tables": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Growth": {
+            "type": "object",
+            "properties": {
+                "row_delta": {
+                    "type": "integer"
+                },
+                "rows_per_day": {
+                    "type": "number"
+                },
+                "size_mb_delta": {
+                    "type": "number"
+                },
+                "size_mb_per_day": {
+                    "type": "number"
+                },
+                "snapshots": {
+                    "type": "integer"
+                },
+                "window_days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Index": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "definition": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_primary": {
+                    "type": "boolean"
+                },
+                "is_unique": {
+                    "type": "boolean"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "predicate": {
+                    "type": "string"
+                },
+                "scans": {
+                    "type": "integer"
+                },
+                "size_mb": {
+                    "type": "number"
+                },
+                "table_id": {
+                    "type": "string"
+                },
+                "tuples_fetched": {
+                    "type": "integer"
+                },
+                "tuples_read": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Schedule": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "cron": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "interval_seconds": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "description": "LastError explains why the last due run could not be queued.",
+                    "type": "string"
+                },
+                "last_job_id": {
+                    "type": "string"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "source_id": {
+                    "type": "string"
+                },
+                "target": {
+                    "$ref": "#/definitions/domain.ConnectionDetails"
+                }
+            }
+        },
+        "domain.ScheduleRequest": {
+            "type": "object",
+            "properties": {
+                "cron": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "string"
+                },
+                "source_id": {
+                    "type": "string"
+                },
+                "target": {
+                    "$ref": "#/definitions/domain.ConnectionDetails"
+                }
+            }
+        },
+        "domain.Schema": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "summary_id": {
+                    "type": "string"
+                },
+                "synced_at": {
+                    "type": "string"
+                },
+                "tables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Table"
+                    }
+                }
+            }
+        },
+        "domain.SchemaListItem": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "summary_id": {
+                    "type": "string"
+                },
+                "table_count": {
+                    "type": "integer"
+                },
+                "total_size_mb": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.SchemaPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.SchemaListItem"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.SchemaRename": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SearchMatch": {
+            "type": "object",
+            "properties": {
+                "dbname": {
+                    "type": "string"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "row_count": {
+                    "type": "integer"
+                },
+                "schema_name": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "size_mb": {
+                    "type": "number"
+                },
+                "source_id": {
+                    "type": "string"
+                },
+                "summary_id": {
+                    "type": "string"
+                },
+                "synced_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SearchPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.SearchMatch"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Server": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_discovered_at": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Source"
+                    }
+                }
+            }
+        },
+        "domain.ServerDiscovery": {
+            "type": "object",
+            "properties": {
+                "databases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "queued": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.BatchSyncResult"
+                    }
+                },
+                "server": {
+                    "$ref": "#/definitions/domain.Server"
+                }
+            }
+        },
+        "domain.SizeBucket": {
+            "type": "object",
+            "properties": {
+                "databases": {
+                    "type": "integer"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "max_mb": {
+                    "type": "number"
+                },
+                "min_mb": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.Source": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "dbname": {
+                    "type": "string"
+                },
+                "has_password": {
+                    "type": "boolean"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_synced_at": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "registered": {
+                    "description": "Registered is set once the source is saved through the sources API.\nSyncs and discoveries of an unsaved database create an unregistered\nsource that only records the target.",
+                    "type": "boolean"
+                },
+                "server_id": {
+                    "description": "ServerID links the source to the Server it was discovered on.",
+                    "type": "string"
+                },
+                "sslmode": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SourceTrends": {
+            "type": "object",
+            "properties": {
+                "database": {
+                    "$ref": "#/definitions/domain.DatabaseTrend"
+                },
+                "dbname": {
+                    "type": "string"
+                },
+                "forecast": {
+                    "$ref": "#/definitions/domain.CapacityForecast"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "source_id": {
+                    "type": "string"
+                },
+                "synced_at": {
+                    "type": "string"
+                },
+                "tables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.TableTrend"
+                    }
+                }
+            }
+        },
+        "domain.Summary": {
+            "type": "object",
+            "properties": {
+                "deleted_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Schema"
+                    }
+                },
+                "source_id": {
+                    "type": "string"
+                },
+                "source_info": {
+                    "$ref": "#/definitions/domain.ConnectionDetails"
+                },
+                "synced_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SummaryDiff": {
+            "type": "object",
+            "properties": {
+                "added_schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "added_tables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.TableRef"
+                    }
+                },
+                "changed_tables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.TableChange"
+                    }
+                },
+                "from_id": {
+                    "type": "string"
+                },
+                "from_synced_at": {
+                    "type": "string"
+                },
+                "removed_schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "removed_tables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.TableRef"
+                    }
+                },
+                "renamed_schemas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.SchemaRename"
+                    }
+                },
+                "renamed_tables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.TableRename"
+                    }
+                },
+                "row_count_delta": {
+                    "type": "integer"
+                },
+                "size_mb_delta": {
+                    "type": "number"
+                },
+                "to_id": {
+                    "type": "string"
+                },
+                "to_synced_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SummaryListItem": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "schema_count": {
+                    "type": "integer"
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Schema"
+                    }
+                },
+                "source_id": {
+                    "type": "string"
+                },
+                "source_info": {
+                    "$ref": "#/definitions/domain.ConnectionDetails"
+                },
+                "synced_at": {
+                    "type": "string"
+                },
+                "table_count": {
+                    "type": "integer"
+                },
+                "total_size_mb": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.SummaryPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.SummaryListItem"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.SyncJob": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "dbname": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "summary_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SyncRequest": {
+            "type": "object",
+            "properties": {
+                "dbname": {
+                    "type": "string"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "source_id": {
+                    "type": "string"
+                },
+                "sslmode": {
+                    "description": "SSLMode is the libpq sslmode used to connect, e.g. \"require\" or\n\"verify-full\".",
+                    "type": "string"
+                },
+                "user": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Table": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Column"
+                    }
+                },
+                "constraints": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Constraint"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "indexes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Index"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "row_count": {
+                    "type": "integer"
+                },
+                "schema_id": {
+                    "type": "string"
+                },
+                "size_mb": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.TableChange": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "row_count_delta": {
+                    "type": "integer"
+                },
+                "row_count_from": {
+                    "type": "integer"
+                },
+                "row_count_to": {
+                    "type": "integer"
+                },
+                "schema": {
+                    "type": "string"
+                },
+                "size_mb_delta": {
+                    "type": "number"
+                },
+                "size_mb_from": {
+                    "type": "number"
+                },
+                "size_mb_to": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.TableListItem": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "row_count": {
                     "type": "integer"
                 },
                 "schema_id": {
@@ -244,6 +2790,100 @@ const docTemplate = `{
                     "type": "number"
                 }
             }
+        },
+        "domain.TablePage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.TableListItem"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.TableRef": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "row_count": {
+                    "type": "integer"
+                },
+                "schema": {
+                    "type": "string"
+                },
+                "size_mb": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.TableRename": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "schema": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.TableStat": {
+            "type": "object",
+            "properties": {
+                "dbname": {
+                    "type": "string"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "row_count": {
+                    "type": "integer"
+                },
+                "schema_name": {
+                    "type": "string"
+                },
+                "size_mb": {
+                    "type": "number"
+                },
+                "summary_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.TableTrend": {
+            "type": "object",
+            "properties": {
+                "growth": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Growth"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "row_count": {
+                    "type": "integer"
+                },
+                "schema_name": {
+                    "type": "string"
+                },
+                "size_mb": {
+                    "type": "number"
+                }
+            }
         }
     }
 }`
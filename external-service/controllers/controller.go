@@ -1,38 +1,85 @@
 package controllers
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/lokesh2201013/postgres-data-summary/external-service/models"
+	"go.uber.org/zap"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/introspect"
+	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
 )
+
+const introspectTimeout = 30 * time.Second
+
 func GetSummaryPostgres(c *fiber.Ctx) error {
-    var connDetails models.ConnectionDetails
-    if err := c.BodyParser(&connDetails); err != nil {
-        return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
-    }
-     fmt.Println("Received connection details:", connDetails)
-    summary := models.Summary{
-        ID:         "sum123",
-        SourceInfo: connDetails,
-      //  SyncedAt:   time.Now(),
-        Schemas: []models.Schema{
-            {
-                Name: "public",
-                Tables: []models.Table{
-                    {Name: "users", RowCount: 0, SizeMB: 12.5},
-                    {Name: "orders", RowCount: 0, SizeMB: 8.3},
-                },
-            },
-            {
-                Name: "sales",
-                Tables: []models.Table{
-                    {Name: "invoices", RowCount: 300, SizeMB: 150},
-                    {Name: "customers", RowCount: 1500, SizeMB: 100},
-                },
-            },
-        },
-    }
-    fmt.Println("Returning summary:", summary)
-    return c.JSON(summary)
+	var connDetails domain.ConnectionDetails
+	if err := c.BodyParser(&connDetails); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if connDetails.Host == "" || connDetails.User == "" || connDetails.DBName == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Missing required connection details"})
+	}
+
+	logger.Log.Info("Introspecting target database",
+		zap.String("host", connDetails.Host),
+		zap.String("dbname", connDetails.DBName))
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), introspectTimeout)
+	defer cancel()
+
+	summary, err := introspect.Summarize(ctx, connDetails)
+	if err != nil {
+		if errors.Is(err, introspect.ErrRejected) {
+			logger.Log.Warn("Target database rejected the connection", zap.String("host", connDetails.Host), zap.Error(err))
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Target database rejected the connection"})
+		}
+		if errors.Is(err, introspect.ErrConnect) {
+			logger.Log.Error("Failed to connect to target database", zap.String("host", connDetails.Host), zap.Error(err))
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "Failed to connect to target database"})
+		}
+		logger.Log.Error("Failed to read catalog", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to read catalog"})
+	}
+
+	logger.Log.Info("Introspection finished",
+		zap.String("summaryID", summary.ID),
+		zap.Int("schemas", len(summary.Schemas)))
+	return c.JSON(summary)
+}
+
+// ListDatabases returns the names of the databases on the server described
+// by the request. dbname is optional; it selects the database connected to
+// while listing.
+func ListDatabases(c *fiber.Ctx) error {
+	var connDetails domain.ConnectionDetails
+	if err := c.BodyParser(&connDetails); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if connDetails.Host == "" || connDetails.User == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Missing required connection details"})
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), introspectTimeout)
+	defer cancel()
+
+	databases, err := introspect.Databases(ctx, connDetails)
+	if err != nil {
+		if errors.Is(err, introspect.ErrRejected) {
+			logger.Log.Warn("Target database rejected the connection", zap.String("host", connDetails.Host), zap.Error(err))
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Target database rejected the connection"})
+		}
+		if errors.Is(err, introspect.ErrConnect) {
+			logger.Log.Error("Failed to connect to target database", zap.String("host", connDetails.Host), zap.Error(err))
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "Failed to connect to target database"})
+		}
+		logger.Log.Error("Failed to list databases", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to list databases"})
+	}
+
+	logger.Log.Info("Listed databases", zap.String("host", connDetails.Host), zap.Int("databases", len(databases)))
+	return c.JSON(databases)
 }
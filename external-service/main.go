@@ -2,7 +2,6 @@ package main
 
 import (
 	"log"
-	"os"
 
 	"github.com/gofiber/fiber/v2"
 	//"github.com/gofiber/fiber/v2/middleware/logger"
@@ -11,6 +10,7 @@ import (
 	fiberSwagger "github.com/swaggo/fiber-swagger"
 
 	//"github.com/lokesh2201013/postgres-data-summary/internal/handler"
+	"github.com/lokesh2201013/postgres-data-summary/internal/config"
 	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
 	//"github.com/lokesh2201013/postgres-data-summary/internal/repository/external"
 	"github.com/lokesh2201013/postgres-data-summary/external-service/database"
@@ -24,7 +24,14 @@ func main() {
 		log.Println("No .env file found")
 	}
 
-	database.ConnectDB()
+	defaults := config.Default()
+	defaults.Server.Port = ":8000"
+	cfg, err := config.LoadFrom(defaults)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	database.ConnectDB(cfg.Database)
 
      
 
@@ -32,15 +39,11 @@ func main() {
     logger.InitLogger()
 	//app.Use(logger.New())
     app.Use(logger.ZapLogger())
-	routes.SetupRoutes(app)
+	routes.SetupRoutes(app, cfg.Server.ServiceToken)
      app.Get("/swagger/*", fiberSwagger.WrapHandler)
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = ":8000"
-	}
 
-	log.Printf("Server listening on %s", port)
-	if err := app.Listen(port); err != nil {
+	log.Printf("Server listening on %s", cfg.Server.Port)
+	if err := app.Listen(cfg.Server.Port); err != nil {
 		log.Fatalf("server error: %v", err)
 	}
 }
@@ -1,10 +1,30 @@
 package routes
 
 import (
+	"crypto/subtle"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/lokesh2201013/postgres-data-summary/external-service/controllers"
 )
 
-func SetupRoutes(app *fiber.App) {
-	app.Post("/summarypostgres",controllers.GetSummaryPostgres)
-}
\ No newline at end of file
+// SetupRoutes registers the service endpoints. When token is non-empty,
+// callers must present it as a bearer token.
+func SetupRoutes(app *fiber.App, token string) {
+	app.Post("/summarypostgres", requireToken(token), controllers.GetSummaryPostgres)
+	app.Post("/databases", requireToken(token), controllers.ListDatabases)
+}
+
+// requireToken rejects requests without "Authorization: Bearer <token>".
+// An empty token disables the check.
+func requireToken(token string) fiber.Handler {
+	expected := []byte("Bearer " + token)
+	return func(c *fiber.Ctx) error {
+		if token == "" {
+			return c.Next()
+		}
+		if subtle.ConstantTimeCompare([]byte(c.Get(fiber.HeaderAuthorization)), expected) != 1 {
+			return fiber.NewError(fiber.StatusUnauthorized, "missing or invalid service token")
+		}
+		return c.Next()
+	}
+}
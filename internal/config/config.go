@@ -1 +1,366 @@
+// Package config loads the typed configuration shared by the summary service,
+// external-service and the command line tools.
+//
+// Values are resolved in three layers: the defaults returned by Default, then
+// an optional YAML file named by CONFIG_FILE, then environment variables. The
+// env tag of each field names the variable that overrides it; the yaml tag
+// names its key in the file. Durations are written as Go durations ("30s").
 package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/retry"
+	"github.com/lokesh2201013/postgres-data-summary/internal/secrets"
+)
+
+// FileEnv names the environment variable holding the optional YAML file path.
+const FileEnv = "CONFIG_FILE"
+
+// Defaults for the summary client, shared with the external package, which
+// applies them when a ClientConfig or BreakerConfig field is not set.
+const (
+	// DefaultExternalURL is where external-service listens when run locally.
+	DefaultExternalURL = "http://127.0.0.1:8000"
+	// DefaultExternalTimeout bounds a single summary request, including
+	// reading the catalog of the target database.
+	DefaultExternalTimeout    = 60 * time.Second
+	DefaultBreakerThreshold   = 5
+	DefaultBreakerOpenTimeout = time.Minute
+)
+
+type Config struct {
+	Server    ServerConfig    `yaml:"server"`
+	Database  DatabaseConfig  `yaml:"database"`
+	External  ExternalConfig  `yaml:"external"`
+	Sync      SyncConfig      `yaml:"sync"`
+	Scheduler SchedulerConfig `yaml:"scheduler"`
+	Retention RetentionConfig `yaml:"retention"`
+	Secrets   SecretsConfig   `yaml:"secrets"`
+}
+
+type ServerConfig struct {
+	// Port is the Fiber listen address.
+	Port string `yaml:"port" env:"PORT"`
+	// StartupDelay is waited before connecting to the database, giving a
+	// database container started alongside time to accept connections.
+	StartupDelay time.Duration `yaml:"startup_delay" env:"STARTUP_DELAY"`
+	// ServiceToken, when set, is the bearer token external-service requires.
+	ServiceToken string `yaml:"service_token" env:"SERVICE_TOKEN"`
+}
+
+// DatabaseConfig addresses the service's own PostgreSQL database.
+type DatabaseConfig struct {
+	Host            string        `yaml:"host" env:"DB_HOST"`
+	Port            int           `yaml:"port" env:"DB_PORT"`
+	User            string        `yaml:"user" env:"DB_USER"`
+	Password        string        `yaml:"password" env:"DB_PASSWORD"`
+	Name            string        `yaml:"name" env:"DB_NAME"`
+	SSLMode         string        `yaml:"sslmode" env:"DB_SSLMODE"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+}
+
+// DSN returns the libpq keyword/value connection string for the database.
+func (d DatabaseConfig) DSN() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s",
+		d.Host, d.User, d.Password, d.Name, d.Port, d.SSLMode)
+}
+
+// ExternalConfig selects how summaries are fetched and configures the HTTP
+// client for external-service.
+type ExternalConfig struct {
+	// Backend is "http" to call external-service or "direct" to read target
+	// catalogs in-process.
+	Backend            string            `yaml:"backend" env:"SUMMARY_BACKEND"`
+	URL                string            `yaml:"url" env:"EXTERNAL_SERVICE_URL"`
+	Timeout            time.Duration     `yaml:"timeout" env:"EXTERNAL_SERVICE_TIMEOUT"`
+	Token              string            `yaml:"token" env:"EXTERNAL_SERVICE_TOKEN"`
+	Headers            map[string]string `yaml:"headers" env:"EXTERNAL_SERVICE_HEADERS"`
+	CAFile             string            `yaml:"ca_file" env:"EXTERNAL_SERVICE_CA_FILE"`
+	CertFile           string            `yaml:"cert_file" env:"EXTERNAL_SERVICE_CERT_FILE"`
+	KeyFile            string            `yaml:"key_file" env:"EXTERNAL_SERVICE_KEY_FILE"`
+	InsecureSkipVerify bool              `yaml:"insecure_skip_verify" env:"EXTERNAL_SERVICE_INSECURE_SKIP_VERIFY"`
+	// BreakerThreshold consecutive failures of one target open its circuit
+	// for BreakerOpenTimeout.
+	BreakerThreshold   int           `yaml:"breaker_threshold" env:"EXTERNAL_BREAKER_THRESHOLD"`
+	BreakerOpenTimeout time.Duration `yaml:"breaker_open_timeout" env:"EXTERNAL_BREAKER_OPEN_TIMEOUT"`
+}
+
+// SyncConfig tunes the background sync workers and their retries.
+type SyncConfig struct {
+	// Retries is the number of attempts per sync, including the first.
+	Retries int `yaml:"retries" env:"SYNC_RETRIES"`
+	// RetryDelay is the wait after the first failure; it doubles after each
+	// further failure up to RetryMaxDelay.
+	RetryDelay    time.Duration `yaml:"retry_delay" env:"SYNC_RETRY_DELAY"`
+	RetryMaxDelay time.Duration `yaml:"retry_max_delay" env:"SYNC_RETRY_MAX_DELAY"`
+	// RetryMaxElapsed stops retrying a sync that has been failing this long.
+	RetryMaxElapsed time.Duration `yaml:"retry_max_elapsed" env:"SYNC_RETRY_MAX_ELAPSED"`
+	Workers         int           `yaml:"workers" env:"SYNC_WORKERS"`
+	// QueueSize bounds the syncs waiting for a worker. The default fits one
+	// full batch sync.
+	QueueSize int `yaml:"queue_size" env:"SYNC_QUEUE_SIZE"`
+}
+
+// syncRetryJitter spreads out retries of syncs that failed together.
+const syncRetryJitter = 0.2
+
+// RetryPolicy returns the policy sync attempts are retried under.
+func (s SyncConfig) RetryPolicy() retry.Policy {
+	return retry.Policy{
+		MaxAttempts:  s.Retries,
+		InitialDelay: s.RetryDelay,
+		MaxDelay:     s.RetryMaxDelay,
+		Jitter:       syncRetryJitter,
+		MaxElapsed:   s.RetryMaxElapsed,
+	}
+}
+
+type SchedulerConfig struct {
+	// Tick is how often due schedules are checked.
+	Tick time.Duration `yaml:"tick" env:"SCHEDULER_TICK"`
+}
+
+// RetentionConfig prunes old snapshots. Retention is disabled while Days
+// is 0.
+type RetentionConfig struct {
+	// Days is the age after which snapshots, and deleted snapshots, are
+	// purged.
+	Days int `yaml:"days" env:"RETENTION_DAYS"`
+	// Tick is how often expired snapshots are pruned.
+	Tick time.Duration `yaml:"tick" env:"RETENTION_TICK"`
+}
+
+// Period returns Days as a duration.
+func (r RetentionConfig) Period() time.Duration {
+	return time.Duration(r.Days) * 24 * time.Hour
+}
+
+// SecretsConfig holds the keys used to encrypt stored passwords. Keys is a
+// comma separated list of id:base64key pairs; see secrets.ParseKeyring.
+type SecretsConfig struct {
+	Keys      string `yaml:"keys" env:"SECRET_KEYS"`
+	ActiveKey string `yaml:"active_key" env:"SECRET_ACTIVE_KEY"`
+}
+
+// Keyring parses the configured keys, returning nil when none are set.
+func (s SecretsConfig) Keyring() (*secrets.Keyring, error) {
+	if s.Keys == "" {
+		return nil, nil
+	}
+	return secrets.ParseKeyring(s.Keys, s.ActiveKey)
+}
+
+// Default returns the configuration used when nothing is overridden.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:         ":8080",
+			StartupDelay: 5 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Host:            "localhost",
+			Port:            5432,
+			SSLMode:         "disable",
+			MaxOpenConns:    5,
+			MaxIdleConns:    3,
+			ConnMaxLifetime: 30 * time.Minute,
+		},
+		External: ExternalConfig{
+			Backend:            "http",
+			URL:                DefaultExternalURL,
+			Timeout:            DefaultExternalTimeout,
+			BreakerThreshold:   DefaultBreakerThreshold,
+			BreakerOpenTimeout: DefaultBreakerOpenTimeout,
+		},
+		Sync: SyncConfig{
+			Retries:         1,
+			RetryDelay:      2 * time.Second,
+			RetryMaxDelay:   30 * time.Second,
+			RetryMaxElapsed: 2 * time.Minute,
+			Workers:         4,
+			QueueSize:       500,
+		},
+		Scheduler: SchedulerConfig{
+			Tick: 30 * time.Second,
+		},
+		Retention: RetentionConfig{
+			Tick: time.Hour,
+		},
+	}
+}
+
+// Load resolves the configuration starting from Default.
+func Load() (*Config, error) {
+	return LoadFrom(Default())
+}
+
+// LoadFrom resolves the configuration starting from base, applying the file
+// named by CONFIG_FILE and then the environment, and validates the result.
+func LoadFrom(base Config) (*Config, error) {
+	cfg := base
+	if path := os.Getenv(FileEnv); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read config file: %w", err)
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("parse config file %s: %w", path, err)
+		}
+	}
+	if err := applyEnv(reflect.ValueOf(&cfg).Elem()); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Server.Port != "", "server.port (PORT) is required")
+	check(c.Server.StartupDelay >= 0, "server.startup_delay (STARTUP_DELAY) must not be negative")
+
+	db := c.Database
+	check(db.Host != "", "database.host (DB_HOST) is required")
+	check(db.User != "", "database.user (DB_USER) is required")
+	check(db.Name != "", "database.name (DB_NAME) is required")
+	check(db.Port > 0 && db.Port < 65536, "database.port (DB_PORT) must be between 1 and 65535, got %d", db.Port)
+	check(db.MaxOpenConns >= 1, "database.max_open_conns (DB_MAX_OPEN_CONNS) must be at least 1")
+	check(db.MaxIdleConns >= 0 && db.MaxIdleConns <= db.MaxOpenConns,
+		"database.max_idle_conns (DB_MAX_IDLE_CONNS) must be between 0 and max_open_conns")
+	check(db.ConnMaxLifetime >= 0, "database.conn_max_lifetime (DB_CONN_MAX_LIFETIME) must not be negative")
+
+	ext := c.External
+	check(ext.Backend == "http" || ext.Backend == "direct",
+		`external.backend (SUMMARY_BACKEND) must be "http" or "direct", got %q`, ext.Backend)
+	if ext.Backend == "http" {
+		u, err := url.Parse(ext.URL)
+		check(err == nil && u.Scheme != "" && u.Host != "", "external.url (EXTERNAL_SERVICE_URL) must be an absolute URL, got %q", ext.URL)
+	}
+	check(ext.Timeout > 0, "external.timeout (EXTERNAL_SERVICE_TIMEOUT) must be positive")
+	check(ext.BreakerThreshold >= 1, "external.breaker_threshold (EXTERNAL_BREAKER_THRESHOLD) must be at least 1")
+	check(ext.BreakerOpenTimeout > 0, "external.breaker_open_timeout (EXTERNAL_BREAKER_OPEN_TIMEOUT) must be positive")
+	check((ext.CertFile == "") == (ext.KeyFile == ""),
+		"external.cert_file and external.key_file must be set together")
+
+	check(c.Sync.Retries >= 1, "sync.retries (SYNC_RETRIES) must be at least 1")
+	check(c.Sync.RetryDelay >= 0, "sync.retry_delay (SYNC_RETRY_DELAY) must not be negative")
+	check(c.Sync.RetryMaxDelay >= 0, "sync.retry_max_delay (SYNC_RETRY_MAX_DELAY) must not be negative")
+	check(c.Sync.RetryMaxElapsed >= 0, "sync.retry_max_elapsed (SYNC_RETRY_MAX_ELAPSED) must not be negative")
+	check(c.Sync.Workers >= 1, "sync.workers (SYNC_WORKERS) must be at least 1")
+	check(c.Sync.QueueSize >= 1, "sync.queue_size (SYNC_QUEUE_SIZE) must be at least 1")
+
+	check(c.Scheduler.Tick >= time.Second, "scheduler.tick (SCHEDULER_TICK) must be at least 1s")
+	check(c.Retention.Days >= 0, "retention.days (RETENTION_DAYS) must not be negative")
+	check(c.Retention.Tick >= time.Minute, "retention.tick (RETENTION_TICK) must be at least 1m")
+
+	if _, err := c.Secrets.Keyring(); err != nil {
+		errs = append(errs, fmt.Errorf("secrets.keys (SECRET_KEYS): %w", err))
+	}
+	check(c.Secrets.ActiveKey == "" || c.Secrets.Keys != "", "secrets.active_key (SECRET_ACTIVE_KEY) requires secrets.keys")
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// applyEnv overrides every field tagged with env whose variable is set.
+func applyEnv(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, value := t.Field(i), v.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			if err := applyEnv(value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := field.Tag.Get("env")
+		raw, ok := os.LookupEnv(name)
+		if name == "" || !ok {
+			continue
+		}
+		if err := setField(value, raw); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func setField(value reflect.Value, raw string) error {
+	switch {
+	case value.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(d))
+	case value.Kind() == reflect.String:
+		value.SetString(raw)
+	case value.Kind() == reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("expected an integer, got %q", raw)
+		}
+		value.SetInt(int64(n))
+	case value.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("expected a boolean, got %q", raw)
+		}
+		value.SetBool(b)
+	case value.Kind() == reflect.Map:
+		headers, err := parsePairs(raw)
+		if err != nil {
+			return err
+		}
+		value.Set(reflect.ValueOf(headers))
+	default:
+		return fmt.Errorf("unsupported field type %s", value.Type())
+	}
+	return nil
+}
+
+// parsePairs parses "Name=value,Other=value" into a map.
+func parsePairs(raw string) (map[string]string, error) {
+	pairs := map[string]string{}
+	if raw == "" {
+		return pairs, nil
+	}
+	for _, pair := range strings.Split(raw, ",") {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("expected Name=value, got %q", pair)
+		}
+		pairs[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return pairs, nil
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/retry"
+)
+
+// requiredEnv sets the settings that have no default.
+func requiredEnv(t *testing.T) {
+	t.Setenv(FileEnv, "")
+	t.Setenv("DB_USER", "user")
+	t.Setenv("DB_NAME", "db")
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	requiredEnv(t)
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, ":8080", cfg.Server.Port)
+	assert.Equal(t, 5, cfg.Database.MaxOpenConns)
+	assert.Equal(t, 30*time.Minute, cfg.Database.ConnMaxLifetime)
+	assert.Equal(t, 1, cfg.Sync.Retries)
+	assert.Equal(t, 2*time.Second, cfg.Sync.RetryDelay)
+	assert.Equal(t, retry.Policy{
+		MaxAttempts:  1,
+		InitialDelay: 2 * time.Second,
+		MaxDelay:     30 * time.Second,
+		Jitter:       syncRetryJitter,
+		MaxElapsed:   2 * time.Minute,
+	}, cfg.Sync.RetryPolicy())
+	assert.Equal(t, "http", cfg.External.Backend)
+	assert.Equal(t, "host=localhost user=user password= dbname=db port=5432 sslmode=disable", cfg.Database.DSN())
+}
+
+func TestLoad_FileThenEnv(t *testing.T) {
+	requiredEnv(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`
+server:
+  port: ":9090"
+database:
+  host: pg.internal
+  max_open_conns: 20
+  max_idle_conns: 10
+external:
+  backend: direct
+  headers:
+    X-Team: blue
+sync:
+  retries: 3
+  retry_delay: 500ms
+  workers: 8
+retention:
+  days: 30
+`), 0o600)
+	assert.NoError(t, err)
+	t.Setenv(FileEnv, path)
+	t.Setenv("SYNC_WORKERS", "16")
+	t.Setenv("EXTERNAL_SERVICE_HEADERS", "X-Env=prod")
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, ":9090", cfg.Server.Port)
+	assert.Equal(t, "pg.internal", cfg.Database.Host)
+	assert.Equal(t, 20, cfg.Database.MaxOpenConns)
+	assert.Equal(t, "direct", cfg.External.Backend)
+	assert.Equal(t, 3, cfg.Sync.Retries)
+	assert.Equal(t, 500*time.Millisecond, cfg.Sync.RetryDelay)
+	assert.Equal(t, 16, cfg.Sync.Workers, "environment overrides the file")
+	assert.Equal(t, map[string]string{"X-Env": "prod"}, cfg.External.Headers)
+	assert.Equal(t, 500, cfg.Sync.QueueSize, "unset values keep their default")
+	assert.Equal(t, 30*24*time.Hour, cfg.Retention.Period())
+	assert.Equal(t, time.Hour, cfg.Retention.Tick)
+}
+
+func TestLoad_RejectsUnknownFileKeys(t *testing.T) {
+	requiredEnv(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("sync:\n  retrys: 3\n"), 0o600))
+	t.Setenv(FileEnv, path)
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "retrys")
+}
+
+func TestLoad_MalformedEnv(t *testing.T) {
+	requiredEnv(t)
+	t.Setenv("SYNC_RETRY_DELAY", "2")
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "SYNC_RETRY_DELAY")
+}
+
+func TestValidate_ReportsEveryProblem(t *testing.T) {
+	cfg := Default()
+	cfg.Database.User = "user"
+	cfg.Database.Name = "db"
+	cfg.Database.MaxIdleConns = 10
+	cfg.External.Backend = "grpc"
+	cfg.Sync.Retries = 0
+	cfg.Secrets.Keys = "k1:not-a-key"
+
+	err := cfg.Validate()
+	assert.ErrorContains(t, err, "DB_MAX_IDLE_CONNS")
+	assert.ErrorContains(t, err, "SUMMARY_BACKEND")
+	assert.ErrorContains(t, err, "SYNC_RETRIES")
+	assert.ErrorContains(t, err, "SECRET_KEYS")
+}
+
+func TestSecretsConfig_Keyring(t *testing.T) {
+	ring, err := SecretsConfig{}.Keyring()
+	assert.NoError(t, err)
+	assert.Nil(t, ring)
+
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	ring, err = SecretsConfig{Keys: "k1:" + key}.Keyring()
+	assert.NoError(t, err)
+	assert.Equal(t, "k1", ring.ActiveKeyID())
+}
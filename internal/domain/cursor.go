@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"time"
+)
+
+// ErrInvalidCursor is returned by ParseSummaryCursor for malformed cursors.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// SummaryCursor marks the last summary of a page of the summaries list. The
+// next page starts after the summary ordered by (sort value, ID). A cursor
+// is only valid for the sort and direction it was issued for.
+type SummaryCursor struct {
+	Sort string `json:"s"`
+	Desc bool   `json:"d,omitempty"`
+	// SyncedAt is the sort value for SortSyncedAt, Value the one for the
+	// other sort keys, as exact decimal text so numeric totals round-trip.
+	SyncedAt time.Time `json:"t,omitempty"`
+	Value    string    `json:"v,omitempty"`
+	ID       string    `json:"id"`
+}
+
+// cursorValue matches the decimal text of a numeric sort value.
+var cursorValue = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// Encode returns the opaque form of c sent to clients as next_cursor.
+func (c SummaryCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParseSummaryCursor decodes a cursor produced by SummaryCursor.Encode.
+func ParseSummaryCursor(raw string) (*SummaryCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c SummaryCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	if c.Sort != SortSyncedAt && !cursorValue.MatchString(c.Value) {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
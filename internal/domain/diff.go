@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+// SummaryDiff describes how a database changed between two stored summaries.
+// Tables inside a renamed schema are reported under their new schema name.
+type SummaryDiff struct {
+	FromID         string         `json:"from_id"`
+	ToID           string         `json:"to_id"`
+	FromSyncedAt   time.Time      `json:"from_synced_at"`
+	ToSyncedAt     time.Time      `json:"to_synced_at"`
+	AddedSchemas   []string       `json:"added_schemas"`
+	RemovedSchemas []string       `json:"removed_schemas"`
+	RenamedSchemas []SchemaRename `json:"renamed_schemas"`
+	AddedTables    []TableRef     `json:"added_tables"`
+	RemovedTables  []TableRef     `json:"removed_tables"`
+	RenamedTables  []TableRename  `json:"renamed_tables"`
+	ChangedTables  []TableChange  `json:"changed_tables"`
+	RowCountDelta  int64          `json:"row_count_delta"`
+	SizeMBDelta    float64        `json:"size_mb_delta"`
+}
+
+type SchemaRename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// TableRef identifies a table and carries its headline numbers.
+type TableRef struct {
+	Schema   string  `json:"schema"`
+	Name     string  `json:"name"`
+	RowCount int64   `json:"row_count"`
+	SizeMB   float64 `json:"size_mb"`
+}
+
+type TableRename struct {
+	Schema string `json:"schema"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+// TableChange records row count and size movement of a table present in
+// both summaries.
+type TableChange struct {
+	Schema        string  `json:"schema"`
+	Name          string  `json:"name"`
+	RowCountFrom  int64   `json:"row_count_from"`
+	RowCountTo    int64   `json:"row_count_to"`
+	RowCountDelta int64   `json:"row_count_delta"`
+	SizeMBFrom    float64 `json:"size_mb_from"`
+	SizeMBTo      float64 `json:"size_mb_to"`
+	SizeMBDelta   float64 `json:"size_mb_delta"`
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// idNamespace scopes the name-based (version 5) UUIDs below so they cannot
+// collide with IDs derived the same way by other applications.
+var idNamespace = uuid.MustParse("6f1c3b52-8a4e-4c1d-9f0e-2b7d5a9c4e31")
+
+// SourceKey is the canonical "host:port/dbname" name of a target database.
+func SourceKey(host string, port int, dbName string) string {
+	return fmt.Sprintf("%s:%d/%s", host, port, dbName)
+}
+
+// SourceID returns the stable ID of the source at host, port and dbName. The
+// same target always maps to the same ID, and different targets never share
+// one. The ID is fixed when the source is first seen; editing a saved
+// source's target later does not change it.
+func SourceID(host string, port int, dbName string) string {
+	return uuid.NewSHA1(idNamespace, []byte(SourceKey(host, port, dbName))).String()
+}
+
+// SnapshotID returns the ID of the summary of sourceID taken at syncedAt.
+// Snapshots of different sources, or of one source at different times,
+// never share an ID.
+func SnapshotID(sourceID string, syncedAt time.Time) string {
+	name := sourceID + "@" + syncedAt.UTC().Format(time.RFC3339Nano)
+	return uuid.NewSHA1(idNamespace, []byte(name)).String()
+}
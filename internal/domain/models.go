@@ -1,21 +1,165 @@
 package domain
 
-import "time"
+import (
+	"time"
 
+	"gorm.io/gorm"
+)
 
+
+// ConnectionDetails addresses a target database. Password is accepted in
+// requests but never persisted in plaintext nor included in responses; it is
+// stored only as ciphertext on Source and Schedule.
 type ConnectionDetails struct {
 	Host     string `json:"host"`
 	Port     *int    `json:"port"`
 	User     string `json:"user"`
-	Password string `json:"password"`
+	Password string `json:"password,omitempty" gorm:"-"`
 	DBName   string `json:"dbname"`
+	// SSLMode is the libpq sslmode used to connect, e.g. "require" or
+	// "verify-full".
+	SSLMode  string `json:"sslmode,omitempty"`
+}
+
+// DefaultPort is used when ConnectionDetails.Port is not provided.
+const DefaultPort = 5432
+
+// DefaultSSLMode is used when ConnectionDetails.SSLMode is not provided. It
+// encrypts the connection when the server supports TLS.
+const DefaultSSLMode = "prefer"
+
+// PortOrDefault returns the configured port or DefaultPort.
+func (d ConnectionDetails) PortOrDefault() int {
+	if d.Port == nil {
+		return DefaultPort
+	}
+	return *d.Port
+}
+
+// SSLModeOrDefault returns the configured sslmode or DefaultSSLMode.
+func (d ConnectionDetails) SSLModeOrDefault() string {
+	if d.SSLMode == "" {
+		return DefaultSSLMode
+	}
+	return d.SSLMode
+}
+
+// Source is a database identified by host, port and name. Every sync of a
+// source stores a new, immutable Summary snapshot linked to it. Sources
+// double as saved connection profiles: when a password is stored it is kept
+// encrypted and never serialised.
+type Source struct {
+	ID                 string     `json:"id" gorm:"primaryKey"`
+	Host               string     `json:"host" gorm:"uniqueIndex:idx_sources_target"`
+	Port               int        `json:"port" gorm:"uniqueIndex:idx_sources_target"`
+	DBName             string     `json:"dbname" gorm:"uniqueIndex:idx_sources_target"`
+	User               string     `json:"user"`
+	PasswordCiphertext string     `json:"-"`
+	HasPassword        bool       `json:"has_password"`
+	SSLMode            string     `json:"sslmode,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+	LastSyncedAt       *time.Time `json:"last_synced_at"`
+	// ServerID links the source to the Server it was discovered on.
+	ServerID *string `json:"server_id,omitempty" gorm:"index"`
+	// Registered is set once the source is saved through the sources API.
+	// Syncs and discoveries of an unsaved database create an unregistered
+	// source that only records the target.
+	Registered bool `json:"registered"`
+}
+
+// Server is a PostgreSQL server (cluster) identified by host and port.
+// Discovering it syncs every database on the server; the Sources of those
+// databases are grouped under it.
+type Server struct {
+	ID               string     `json:"id" gorm:"primaryKey"`
+	Host             string     `json:"host" gorm:"uniqueIndex:idx_servers_target"`
+	Port             int        `json:"port" gorm:"uniqueIndex:idx_servers_target"`
+	CreatedAt        time.Time  `json:"created_at"`
+	LastDiscoveredAt *time.Time `json:"last_discovered_at"`
+	Sources          []Source   `json:"sources,omitempty" gorm:"foreignKey:ServerID;references:ID"`
 }
+
+// ServerDiscovery reports one discovery of a server: the databases found on
+// it and the sync queued for each of them, in Databases order.
+type ServerDiscovery struct {
+	Server    Server            `json:"server"`
+	Databases []string          `json:"databases"`
+	Queued    int               `json:"queued"`
+	Failed    int               `json:"failed"`
+	Results   []BatchSyncResult `json:"results"`
+}
+
+// SyncRequest is the body of a sync call: either the ID of a saved Source or
+// raw connection details.
+type SyncRequest struct {
+	SourceID string `json:"source_id"`
+	ConnectionDetails
+}
+
+// Summary is one snapshot of a source database. Deleting a summary only sets
+// DeletedAt, hiding it from every read until it is restored or purged.
 type Summary struct {
     ID        string    `json:"id" gorm:"primaryKey"`
+    SourceID  string    `json:"source_id" gorm:"index"`
     Name      string    `json:"name"`
-    SyncedAt  time.Time `json:"synced_at"`
+    SyncedAt  time.Time `json:"synced_at" gorm:"index"`
     SourceInfo ConnectionDetails `json:"source_info" gorm:"embedded;embeddedPrefix:source_"`
     Schemas   []Schema  `json:"schemas" gorm:"foreignKey:SummaryID;references:ID"`
+    DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index" swaggertype:"string" format:"date-time"`
+}
+
+// Sort keys accepted in SummaryQuery.Sort.
+const (
+	SortSyncedAt   = "synced_at"
+	SortTotalSize  = "total_size"
+	SortTableCount = "table_count"
+)
+
+// SummaryQuery filters, searches and orders the summaries list. Zero fields
+// do not filter. Q matches schema and table names case-insensitively by
+// substring. Sort defaults to SortSyncedAt; Desc puts the newest or largest
+// summaries first.
+type SummaryQuery struct {
+	Host         string
+	DBName       string
+	SyncedAfter  *time.Time
+	SyncedBefore *time.Time
+	// HasTable keeps summaries containing a table with exactly this name in
+	// any schema.
+	HasTable string
+	// MinSizeMB keeps summaries whose tables total at least this size.
+	MinSizeMB float64
+	Q         string
+	Sort      string
+	Desc      bool
+	// After continues the list after the last summary of a previous page.
+	After *SummaryCursor
+	// Compact leaves the schema tree out of the listed items.
+	Compact bool
+}
+
+// SummaryListItem is a summary as listed by GET /summary/summaries: its
+// header, headline totals and, unless the compact view was requested, its
+// schemas and tables.
+type SummaryListItem struct {
+	ID          string            `json:"id"`
+	SourceID    string            `json:"source_id"`
+	Name        string            `json:"name"`
+	SyncedAt    time.Time         `json:"synced_at"`
+	SourceInfo  ConnectionDetails `json:"source_info"`
+	SchemaCount int               `json:"schema_count"`
+	TableCount  int               `json:"table_count"`
+	TotalSizeMB float64           `json:"total_size_mb"`
+	Schemas     []Schema          `json:"schemas,omitempty"`
+}
+
+// SummaryPage is one page of the summaries list. Total counts every summary
+// matching the filters; NextCursor is empty on the last page.
+type SummaryPage struct {
+	Items      []SummaryListItem `json:"items"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+	Total      int64             `json:"total"`
 }
 
 type Schema struct {
@@ -33,4 +177,363 @@ type Table struct {
     Name     string  `json:"name"`
     RowCount int64   `json:"row_count"`
     SizeMB   float64 `json:"size_mb"`
+
+    Columns []Column `json:"columns" gorm:"foreignKey:TableID;references:ID"`
+    Indexes []Index  `json:"indexes" gorm:"foreignKey:TableID;references:ID"`
+    Constraints []Constraint `json:"constraints" gorm:"foreignKey:TableID;references:ID"`
+}
+
+// Sort keys of the schema and table sub-resource lists, next to
+// SortTotalSize and SortTableCount.
+const (
+	SortName     = "name"
+	SortRowCount = "row_count"
+	SortSizeMB   = "size_mb"
+)
+
+// ListOrder orders a sub-resource list. An empty Sort orders by name.
+type ListOrder struct {
+	Sort string
+	Desc bool
+}
+
+// SchemaListItem is a schema of a summary as listed by
+// GET /summary/summaries/{id}/schemas, without its tables.
+type SchemaListItem struct {
+	ID          string  `json:"id"`
+	SummaryID   string  `json:"summary_id"`
+	Name        string  `json:"name"`
+	TableCount  int     `json:"table_count"`
+	TotalSizeMB float64 `json:"total_size_mb"`
+}
+
+// SchemaPage is one page of the schemas of a summary.
+type SchemaPage struct {
+	Items []SchemaListItem `json:"items"`
+	Total int64            `json:"total"`
+}
+
+// TableListItem is a table as listed by
+// GET /summary/summaries/{id}/schemas/{schema}/tables, without its columns,
+// indexes and constraints.
+type TableListItem struct {
+	ID       string  `json:"id"`
+	SchemaID string  `json:"schema_id"`
+	Name     string  `json:"name"`
+	RowCount int64   `json:"row_count"`
+	SizeMB   float64 `json:"size_mb"`
+}
+
+// TablePage is one page of the tables of a schema.
+type TablePage struct {
+	Items []TableListItem `json:"items"`
+	Total int64           `json:"total"`
+}
+
+type Column struct {
+    ID              string  `json:"id" gorm:"primaryKey"`
+    TableID         string  `json:"table_id" gorm:"index"`
+    Name            string  `json:"name"`
+    OrdinalPosition int     `json:"ordinal_position"`
+    DataType        string  `json:"data_type"`
+    IsNullable      bool    `json:"is_nullable"`
+    DefaultValue    *string `json:"default_value"`
+    Collation       *string `json:"collation"`
+    IsIdentity      bool    `json:"is_identity"`
+    IsGenerated     bool    `json:"is_generated"`
+}
+
+type Index struct {
+    ID            string   `json:"id" gorm:"primaryKey"`
+    TableID       string   `json:"table_id" gorm:"index"`
+    Name          string   `json:"name"`
+    Columns       []string `json:"columns" gorm:"serializer:json"`
+    IsUnique      bool     `json:"is_unique"`
+    IsPrimary     bool     `json:"is_primary"`
+    Predicate     *string  `json:"predicate"`
+    Method        string   `json:"method"`
+    Definition    string   `json:"definition"`
+    SizeMB        float64  `json:"size_mb"`
+    Scans         int64    `json:"scans"`
+    TuplesRead    int64    `json:"tuples_read"`
+    TuplesFetched int64    `json:"tuples_fetched"`
+}
+
+// Constraint types as reported in Constraint.Type.
+const (
+    ConstraintPrimaryKey = "primary_key"
+    ConstraintUnique     = "unique"
+    ConstraintCheck      = "check"
+    ConstraintForeignKey = "foreign_key"
+)
+
+// Constraint is a primary key, unique, check or foreign key constraint. The
+// Referenced* and On* fields are only set for foreign keys.
+type Constraint struct {
+    ID                string   `json:"id" gorm:"primaryKey"`
+    TableID           string   `json:"table_id" gorm:"index"`
+    Name              string   `json:"name"`
+    Type              string   `json:"type"`
+    Columns           []string `json:"columns" gorm:"serializer:json"`
+    Definition        string   `json:"definition"`
+    ReferencedSchema  *string  `json:"referenced_schema,omitempty"`
+    ReferencedTable   *string  `json:"referenced_table,omitempty"`
+    ReferencedColumns []string `json:"referenced_columns,omitempty" gorm:"serializer:json"`
+    OnDelete          *string  `json:"on_delete,omitempty"`
+    OnUpdate          *string  `json:"on_update,omitempty"`
+}
+
+// Kinds of objects reported in SearchMatch.Kind.
+const (
+	MatchTable  = "table"
+	MatchSchema = "schema"
+)
+
+// SearchQuery searches table and schema names across stored summaries. Kind
+// limits matches to MatchTable or MatchSchema. Only the newest snapshot of
+// each source is searched unless History is set.
+type SearchQuery struct {
+	Q       string
+	Kind    string
+	History bool
+}
+
+// SearchMatch is a table or schema whose name matches a search, with the
+// summary and database it belongs to. Score is the trigram similarity of
+// Name to the query, from 0 to 1, or 0 when pg_trgm is unavailable. RowCount and SizeMB are only set for
+// tables; SchemaName equals Name for schemas.
+type SearchMatch struct {
+	Kind       string    `json:"kind"`
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	SchemaName string    `json:"schema_name"`
+	SummaryID  string    `json:"summary_id"`
+	SourceID   string    `json:"source_id"`
+	Host       string    `json:"host"`
+	DBName     string    `json:"dbname"`
+	SyncedAt   time.Time `json:"synced_at"`
+	RowCount   *int64    `json:"row_count,omitempty"`
+	SizeMB     *float64  `json:"size_mb,omitempty"`
+	Score      float64   `json:"score"`
+}
+
+// SearchPage is one page of search matches, best first.
+type SearchPage struct {
+	Items []SearchMatch `json:"items"`
+	Total int64         `json:"total"`
+}
+
+// FleetStats aggregates the newest snapshot of every source: how many
+// databases, schemas, tables and rows are tracked and how large they are,
+// the largest tables and databases, and how database sizes are distributed.
+type FleetStats struct {
+	Databases        int64          `json:"databases"`
+	Schemas          int64          `json:"schemas"`
+	Tables           int64          `json:"tables"`
+	RowCount         int64          `json:"row_count"`
+	SizeMB           float64        `json:"size_mb"`
+	LargestTables    []TableStat    `json:"largest_tables"`
+	LargestDatabases []DatabaseStat `json:"largest_databases"`
+	SizeDistribution []SizeBucket   `json:"size_distribution"`
+}
+
+// TableStat is a table of the newest snapshot of a source.
+type TableStat struct {
+	SummaryID  string  `json:"summary_id"`
+	Host       string  `json:"host"`
+	DBName     string  `json:"dbname"`
+	SchemaName string  `json:"schema_name"`
+	Name       string  `json:"name"`
+	RowCount   int64   `json:"row_count"`
+	SizeMB     float64 `json:"size_mb"`
+}
+
+// DatabaseStat totals the newest snapshot of a source.
+type DatabaseStat struct {
+	SummaryID  string    `json:"summary_id"`
+	SourceID   string    `json:"source_id"`
+	Host       string    `json:"host"`
+	DBName     string    `json:"dbname"`
+	SyncedAt   time.Time `json:"synced_at"`
+	TableCount int64     `json:"table_count"`
+	RowCount   int64     `json:"row_count"`
+	SizeMB     float64   `json:"size_mb"`
+}
+
+// SizeBucket counts the databases whose total size is at least MinMB and,
+// unless MaxMB is nil, below MaxMB.
+type SizeBucket struct {
+	Label     string   `json:"label"`
+	MinMB     float64  `json:"min_mb"`
+	MaxMB     *float64 `json:"max_mb,omitempty"`
+	Databases int64    `json:"databases"`
+}
+
+// TrendQuery selects the growth windows of a trend report and the size
+// threshold to forecast against.
+type TrendQuery struct {
+	// Windows are growth windows in days, counted back from now.
+	Windows []int
+	// ThresholdMB, when set, adds a forecast of when the database reaches
+	// this size.
+	ThresholdMB *float64
+}
+
+// TrendPoint is one table of one snapshot of a source, as read back for
+// trend analysis. Schemas without tables yield a point with an empty
+// TableName so every snapshot is represented.
+type TrendPoint struct {
+	SummaryID  string
+	SyncedAt   time.Time
+	Host       string
+	DBName     string
+	SchemaName string
+	TableName  string
+	RowCount   int64
+	SizeMB     float64
+}
+
+// SourceTrends reports how a source database and its tables grew across
+// its stored snapshots.
+type SourceTrends struct {
+	SourceID string            `json:"source_id"`
+	Host     string            `json:"host"`
+	DBName   string            `json:"dbname"`
+	SyncedAt time.Time         `json:"synced_at"`
+	Database DatabaseTrend     `json:"database"`
+	Tables   []TableTrend      `json:"tables"`
+	Forecast *CapacityForecast `json:"forecast,omitempty"`
+}
+
+// DatabaseTrend is the size of the newest snapshot and its growth over
+// each window.
+type DatabaseTrend struct {
+	RowCount int64    `json:"row_count"`
+	SizeMB   float64  `json:"size_mb"`
+	Growth   []Growth `json:"growth"`
+}
+
+// TableTrend is a table of the newest snapshot and its growth over each
+// window.
+type TableTrend struct {
+	SchemaName string   `json:"schema_name"`
+	Name       string   `json:"name"`
+	RowCount   int64    `json:"row_count"`
+	SizeMB     float64  `json:"size_mb"`
+	Growth     []Growth `json:"growth"`
+}
+
+// Growth is the change over one window. Rates are least-squares slopes
+// over the snapshots in the window and stay zero with fewer than two.
+type Growth struct {
+	WindowDays   int     `json:"window_days"`
+	Snapshots    int     `json:"snapshots"`
+	RowDelta     int64   `json:"row_delta"`
+	SizeMBDelta  float64 `json:"size_mb_delta"`
+	RowsPerDay   float64 `json:"rows_per_day"`
+	SizeMBPerDay float64 `json:"size_mb_per_day"`
+}
+
+// CapacityForecast projects when a database crosses ThresholdMB at the
+// size growth rate of its longest window. DaysUntil and CrossesAt are nil
+// when the threshold is already reached, the database is not growing, or
+// the crossing lies more than a century ahead.
+type CapacityForecast struct {
+	ThresholdMB  float64    `json:"threshold_mb"`
+	WindowDays   int        `json:"window_days"`
+	SizeMBPerDay float64    `json:"size_mb_per_day"`
+	Reached      bool       `json:"reached"`
+	DaysUntil    *float64   `json:"days_until,omitempty"`
+	CrossesAt    *time.Time `json:"crosses_at,omitempty"`
+}
+
+// Sync job states reported in SyncJob.Status.
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// SyncJob tracks an asynchronous sync of one database. Credentials are never
+// stored on the job; only the target host and database are recorded.
+type SyncJob struct {
+	ID         string     `json:"id" gorm:"primaryKey"`
+	Status     string     `json:"status" gorm:"index"`
+	Host       string     `json:"host"`
+	DBName     string     `json:"dbname"`
+	Attempts   int        `json:"attempts"`
+	SummaryID  *string    `json:"summary_id"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+// BatchSyncResult reports how one item of a batch sync was queued. Status is
+// JobQueued, with the JobID to poll, or JobFailed when the item was rejected
+// before it reached a worker.
+type BatchSyncResult struct {
+	Index    int    `json:"index"`
+	SourceID string `json:"source_id,omitempty"`
+	Host     string `json:"host"`
+	DBName   string `json:"dbname"`
+	JobID    string `json:"job_id,omitempty"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+// BatchSyncResponse lists the items of a batch sync in request order.
+type BatchSyncResponse struct {
+	Queued  int               `json:"queued"`
+	Failed  int               `json:"failed"`
+	Results []BatchSyncResult `json:"results"`
+}
+
+// Circuit breaker states reported in CircuitState.State.
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half_open"
+)
+
+// CircuitState describes the circuit breaker guarding summary fetches from
+// one target, identified as host:port. While a circuit is open, syncs of
+// that target fail immediately until RetryAt.
+type CircuitState struct {
+	Target              string     `json:"target"`
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastError           string     `json:"last_error,omitempty"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	RetryAt             *time.Time `json:"retry_at,omitempty"`
+}
+
+// Schedule triggers a sync of a saved Source (or of Target when no SourceID
+// is set) either on a cron expression or at a fixed interval. Exactly one of
+// Cron and IntervalSeconds is set. The Target password is kept encrypted in
+// TargetPasswordCiphertext.
+type Schedule struct {
+	ID                       string            `json:"id" gorm:"primaryKey"`
+	SourceID                 string            `json:"source_id,omitempty" gorm:"index"`
+	Target                   ConnectionDetails `json:"target" gorm:"embedded;embeddedPrefix:target_"`
+	TargetPasswordCiphertext string            `json:"-"`
+	Cron                     string            `json:"cron,omitempty"`
+	IntervalSeconds          int64             `json:"interval_seconds,omitempty"`
+	Paused                   bool              `json:"paused"`
+	NextRunAt                time.Time         `json:"next_run_at" gorm:"index"`
+	LastRunAt                *time.Time        `json:"last_run_at"`
+	LastJobID                *string           `json:"last_job_id"`
+	// LastError explains why the last due run could not be queued.
+	LastError                string            `json:"last_error,omitempty"`
+	CreatedAt                time.Time         `json:"created_at"`
+}
+
+// ScheduleRequest is the body accepted when creating a schedule. Interval is
+// a Go duration such as "6h" or "30m".
+type ScheduleRequest struct {
+	SourceID string            `json:"source_id"`
+	Target   ConnectionDetails `json:"target"`
+	Cron     string            `json:"cron"`
+	Interval string            `json:"interval"`
 }
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
+	"github.com/lokesh2201013/postgres-data-summary/internal/repository/external"
+)
+
+type AdminHandler interface {
+	GetCircuits(c *fiber.Ctx) error
+	ResetCircuit(c *fiber.Ctx) error
+}
+
+type adminHandlerImpl struct {
+	circuits external.CircuitMonitor
+}
+
+func NewAdminHandler(circuits external.CircuitMonitor) AdminHandler {
+	return &adminHandlerImpl{circuits: circuits}
+}
+
+// GetCircuits godoc
+// @Summary List circuit breakers
+// @Description Reports the circuit breaker of every target synced since startup. Syncs of a target whose circuit is open fail immediately until retry_at.
+// @Tags admin
+// @Produce  json
+// @Success 200 {array} domain.CircuitState
+// @Router /summary/admin/circuits [get]
+func (h *adminHandlerImpl) GetCircuits(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(h.circuits.Circuits())
+}
+
+// ResetCircuit godoc
+// @Summary Close a circuit breaker
+// @Description Closes the circuit of a target so the next sync contacts it immediately
+// @Tags admin
+// @Param target path string true "Target as host:port"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /summary/admin/circuits/{target}/reset [post]
+func (h *adminHandlerImpl) ResetCircuit(c *fiber.Ctx) error {
+	target, err := url.PathUnescape(c.Params("target"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid target")
+	}
+	if !h.circuits.ResetCircuit(target) {
+		return fiber.NewError(fiber.StatusNotFound, "Circuit not found")
+	}
+	logger.Log.Info("Circuit reset", zap.String("target", target))
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,62 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/handler"
+	"github.com/lokesh2201013/postgres-data-summary/internal/router"
+)
+
+type mockCircuitMonitor struct {
+	mock.Mock
+}
+
+func (m *mockCircuitMonitor) Circuits() []domain.CircuitState {
+	return m.Called().Get(0).([]domain.CircuitState)
+}
+
+func (m *mockCircuitMonitor) ResetCircuit(target string) bool {
+	return m.Called(target).Bool(0)
+}
+
+func setupAdminApp(monitor *mockCircuitMonitor) *fiber.App {
+	app := fiber.New()
+	router.AdminRoutes(app, handler.NewAdminHandler(monitor))
+	return app
+}
+
+func TestGetCircuits(t *testing.T) {
+	monitor := new(mockCircuitMonitor)
+	monitor.On("Circuits").Return([]domain.CircuitState{
+		{Target: "db:5432", State: domain.CircuitOpen, ConsecutiveFailures: 5, LastError: "connection refused"},
+	})
+
+	resp, _ := setupAdminApp(monitor).Test(httptest.NewRequest(http.MethodGet, "/summary/admin/circuits", nil))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var states []domain.CircuitState
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&states))
+	assert.Equal(t, "db:5432", states[0].Target)
+	assert.Equal(t, domain.CircuitOpen, states[0].State)
+}
+
+func TestResetCircuit(t *testing.T) {
+	monitor := new(mockCircuitMonitor)
+	monitor.On("ResetCircuit", "db:5432").Return(true)
+	monitor.On("ResetCircuit", "other:5432").Return(false)
+	app := setupAdminApp(monitor)
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodPost, "/summary/admin/circuits/db:5432/reset", nil))
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp, _ = app.Test(httptest.NewRequest(http.MethodPost, "/summary/admin/circuits/other%3A5432/reset", nil))
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
+	"github.com/lokesh2201013/postgres-data-summary/internal/service"
+)
+
+type ScheduleHandler interface {
+	CreateSchedule(c *fiber.Ctx) error
+	GetSchedules(c *fiber.Ctx) error
+	GetSchedule(c *fiber.Ctx) error
+	PauseSchedule(c *fiber.Ctx) error
+	ResumeSchedule(c *fiber.Ctx) error
+	DeleteSchedule(c *fiber.Ctx) error
+}
+
+type scheduleHandlerImpl struct {
+	service service.IScheduleService
+}
+
+func NewScheduleHandler(service service.IScheduleService) ScheduleHandler {
+	return &scheduleHandlerImpl{service: service}
+}
+
+// CreateSchedule godoc
+// @Summary Create a recurring sync schedule
+// @Description Registers a database to be synced on a cron expression (e.g. "0 3 * * *") or a fixed interval (e.g. "6h")
+// @Tags schedules
+// @Accept  json
+// @Produce  json
+// @Param schedule body domain.ScheduleRequest true "Target and cron or interval"
+// @Success 201 {object} domain.Schedule
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /summary/schedules [post]
+func (h *scheduleHandlerImpl) CreateSchedule(c *fiber.Ctx) error {
+	var req domain.ScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse ScheduleRequest", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	schedule, err := h.service.CreateSchedule(req)
+	if err != nil {
+		return scheduleError(err, "create schedule")
+	}
+	return c.Status(fiber.StatusCreated).JSON(redactSchedule(*schedule))
+}
+
+// GetSchedules godoc
+// @Summary List sync schedules
+// @Tags schedules
+// @Produce  json
+// @Success 200 {array} domain.Schedule
+// @Failure 500 {object} map[string]string
+// @Router /summary/schedules [get]
+func (h *scheduleHandlerImpl) GetSchedules(c *fiber.Ctx) error {
+	schedules, err := h.service.GetSchedules()
+	if err != nil {
+		return scheduleError(err, "get schedules")
+	}
+	for i := range schedules {
+		schedules[i] = redactSchedule(schedules[i])
+	}
+	return c.Status(fiber.StatusOK).JSON(schedules)
+}
+
+// GetSchedule godoc
+// @Summary Get a sync schedule
+// @Tags schedules
+// @Produce  json
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} domain.Schedule
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /summary/schedules/{id} [get]
+func (h *scheduleHandlerImpl) GetSchedule(c *fiber.Ctx) error {
+	schedule, err := h.service.GetSchedule(c.Params("id"))
+	if err != nil {
+		return scheduleError(err, "get schedule")
+	}
+	return c.Status(fiber.StatusOK).JSON(redactSchedule(*schedule))
+}
+
+// PauseSchedule godoc
+// @Summary Pause a sync schedule
+// @Tags schedules
+// @Produce  json
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} domain.Schedule
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /summary/schedules/{id}/pause [post]
+func (h *scheduleHandlerImpl) PauseSchedule(c *fiber.Ctx) error {
+	schedule, err := h.service.PauseSchedule(c.Params("id"))
+	if err != nil {
+		return scheduleError(err, "pause schedule")
+	}
+	return c.Status(fiber.StatusOK).JSON(redactSchedule(*schedule))
+}
+
+// ResumeSchedule godoc
+// @Summary Resume a paused sync schedule
+// @Description Reactivates a schedule; runs missed while paused are skipped
+// @Tags schedules
+// @Produce  json
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} domain.Schedule
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /summary/schedules/{id}/resume [post]
+func (h *scheduleHandlerImpl) ResumeSchedule(c *fiber.Ctx) error {
+	schedule, err := h.service.ResumeSchedule(c.Params("id"))
+	if err != nil {
+		return scheduleError(err, "resume schedule")
+	}
+	return c.Status(fiber.StatusOK).JSON(redactSchedule(*schedule))
+}
+
+// DeleteSchedule godoc
+// @Summary Delete a sync schedule
+// @Tags schedules
+// @Param id path string true "Schedule ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /summary/schedules/{id} [delete]
+func (h *scheduleHandlerImpl) DeleteSchedule(c *fiber.Ctx) error {
+	if err := h.service.DeleteSchedule(c.Params("id")); err != nil {
+		return scheduleError(err, "delete schedule")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// scheduleError maps service errors to HTTP errors, logging unexpected ones.
+func scheduleError(err error, action string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidSchedule), errors.Is(err, service.ErrSecretsDisabled):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrScheduleNotFound):
+		return fiber.NewError(fiber.StatusNotFound, "Schedule not found")
+	}
+	logger.Log.Error("Schedule request failed", zap.String("action", action), zap.Error(err))
+	return fiber.NewError(fiber.StatusInternalServerError, "Failed to "+action)
+}
+
+// redactSchedule strips the target password from API responses. Stored
+// schedules only carry its ciphertext, which is never serialised.
+func redactSchedule(schedule domain.Schedule) domain.Schedule {
+	schedule.Target.Password = ""
+	return schedule
+}
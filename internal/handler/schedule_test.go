@@ -0,0 +1,118 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/handler"
+	"github.com/lokesh2201013/postgres-data-summary/internal/router"
+	"github.com/lokesh2201013/postgres-data-summary/internal/service"
+)
+
+type mockScheduleService struct {
+	mock.Mock
+}
+
+func (m *mockScheduleService) schedule(args mock.Arguments) (*domain.Schedule, error) {
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Schedule), args.Error(1)
+}
+
+func (m *mockScheduleService) CreateSchedule(req domain.ScheduleRequest) (*domain.Schedule, error) {
+	return m.schedule(m.Called(req))
+}
+
+func (m *mockScheduleService) GetSchedules() ([]domain.Schedule, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Schedule), args.Error(1)
+}
+
+func (m *mockScheduleService) GetSchedule(id string) (*domain.Schedule, error) {
+	return m.schedule(m.Called(id))
+}
+
+func (m *mockScheduleService) PauseSchedule(id string) (*domain.Schedule, error) {
+	return m.schedule(m.Called(id))
+}
+
+func (m *mockScheduleService) ResumeSchedule(id string) (*domain.Schedule, error) {
+	return m.schedule(m.Called(id))
+}
+
+func (m *mockScheduleService) DeleteSchedule(id string) error {
+	return m.Called(id).Error(0)
+}
+
+func setupScheduleApp(svc *mockScheduleService) *fiber.App {
+	app := fiber.New()
+	router.ScheduleRoutes(app, handler.NewScheduleHandler(svc))
+	return app
+}
+
+func TestCreateSchedule_RedactsPassword(t *testing.T) {
+	svc := new(mockScheduleService)
+	app := setupScheduleApp(svc)
+
+	req := domain.ScheduleRequest{
+		Target:   domain.ConnectionDetails{Host: "db", User: "u", Password: "secret", DBName: "demo"},
+		Interval: "1h",
+	}
+	svc.On("CreateSchedule", req).Return(&domain.Schedule{ID: "s1", Target: req.Target, IntervalSeconds: 3600}, nil)
+
+	body, _ := json.Marshal(req)
+	httpReq := httptest.NewRequest(http.MethodPost, "/summary/schedules", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(httpReq)
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var schedule domain.Schedule
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&schedule))
+	assert.Equal(t, "s1", schedule.ID)
+	assert.Empty(t, schedule.Target.Password)
+}
+
+func TestCreateSchedule_Invalid(t *testing.T) {
+	svc := new(mockScheduleService)
+	app := setupScheduleApp(svc)
+
+	svc.On("CreateSchedule", mock.Anything).Return(nil, service.ErrInvalidSchedule)
+
+	httpReq := httptest.NewRequest(http.MethodPost, "/summary/schedules", bytes.NewReader([]byte(`{"target":{"host":"db"}}`)))
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(httpReq)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestPauseSchedule_NotFound(t *testing.T) {
+	svc := new(mockScheduleService)
+	app := setupScheduleApp(svc)
+
+	svc.On("PauseSchedule", "missing").Return(nil, service.ErrScheduleNotFound)
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodPost, "/summary/schedules/missing/pause", nil))
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestDeleteSchedule_Success(t *testing.T) {
+	svc := new(mockScheduleService)
+	app := setupScheduleApp(svc)
+
+	svc.On("DeleteSchedule", "s1").Return(nil)
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodDelete, "/summary/schedules/s1", nil))
+
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
+	"github.com/lokesh2201013/postgres-data-summary/internal/service"
+)
+
+type SearchHandler interface {
+	Search(c *fiber.Ctx) error
+}
+
+type searchHandlerImpl struct {
+	service service.ISearchService
+}
+
+func NewSearchHandler(service service.ISearchService) SearchHandler {
+	return &searchHandlerImpl{service: service}
+}
+
+// Search godoc
+// @Summary Search tables and schemas across databases
+// @Description Finds tables and schemas whose names are similar to or contain q across all stored summaries, with the summary, host and database of each match. Exact name matches rank first, then by trigram similarity. Only the newest snapshot of each source is searched unless history is set.
+// @Tags search
+// @Produce  json
+// @Param q query string true "Table or schema name to look for"
+// @Param kind query string false "Only match tables or only schemas" Enums(table, schema)
+// @Param history query bool false "Also search older snapshots"
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Success 200 {object} domain.SearchPage
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /summary/search [get]
+func (h *searchHandlerImpl) Search(c *fiber.Ctx) error {
+	query := domain.SearchQuery{
+		Q:       c.Query("q"),
+		Kind:    c.Query("kind"),
+		History: c.QueryBool("history"),
+	}
+	page, pageSize := pageParams(c)
+
+	result, err := h.service.Search(query, page, pageSize)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidQuery) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		logger.Log.Error("Search failed", zap.String("q", query.Q), zap.Error(err))
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to search")
+	}
+	return c.Status(fiber.StatusOK).JSON(result)
+}
@@ -0,0 +1,64 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/handler"
+	"github.com/lokesh2201013/postgres-data-summary/internal/router"
+	"github.com/lokesh2201013/postgres-data-summary/internal/service"
+)
+
+type mockSearchService struct {
+	mock.Mock
+}
+
+func (m *mockSearchService) Search(query domain.SearchQuery, page, pageSize int) (*domain.SearchPage, error) {
+	args := m.Called(query, page, pageSize)
+	if p := args.Get(0); p != nil {
+		return p.(*domain.SearchPage), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func setupSearchApp(svc *mockSearchService) *fiber.App {
+	app := fiber.New()
+	router.SearchRoutes(app, handler.NewSearchHandler(svc))
+	return app
+}
+
+func TestSearch(t *testing.T) {
+	svc := new(mockSearchService)
+	app := setupSearchApp(svc)
+
+	svc.On("Search", domain.SearchQuery{Q: "invoices", Kind: domain.MatchTable, History: true}, 1, 20).
+		Return(&domain.SearchPage{Items: []domain.SearchMatch{{Kind: domain.MatchTable, Name: "invoices", Host: "pg1", DBName: "billing", Score: 1}}, Total: 1}, nil)
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/summary/search?q=invoices&kind=table&history=true&pageSize=20", nil))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var page domain.SearchPage
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&page))
+	assert.Equal(t, "billing", page.Items[0].DBName)
+}
+
+func TestSearch_Errors(t *testing.T) {
+	svc := new(mockSearchService)
+	app := setupSearchApp(svc)
+
+	svc.On("Search", domain.SearchQuery{}, 1, 10).Return(nil, fmt.Errorf("%w: q is required", service.ErrInvalidQuery))
+	svc.On("Search", domain.SearchQuery{Q: "orders"}, 1, 10).Return(nil, errors.New("db down"))
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/summary/search", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp, _ = app.Test(httptest.NewRequest(http.MethodGet, "/summary/search?q=orders", nil))
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
+	"github.com/lokesh2201013/postgres-data-summary/internal/service"
+)
+
+type ServerHandler interface {
+	DiscoverServer(c *fiber.Ctx) error
+	GetServers(c *fiber.Ctx) error
+	GetServer(c *fiber.Ctx) error
+}
+
+type serverHandlerImpl struct {
+	service service.IServerService
+}
+
+func NewServerHandler(service service.IServerService) ServerHandler {
+	return &serverHandlerImpl{service: service}
+}
+
+// DiscoverServer godoc
+// @Summary Sync every database on a server
+// @Description Lists the databases of the server (excluding templates), groups their sources under a server record and queues a background sync of each with the given credentials. The response lists each database's job ID without waiting for the syncs. dbname is optional and only selects the database connected to for the listing (default postgres).
+// @Tags servers
+// @Accept  json
+// @Produce  json
+// @Param details body domain.ConnectionDetails true "Server connection"
+// @Success 202 {object} domain.ServerDiscovery
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /summary/servers/discover [post]
+func (h *serverHandlerImpl) DiscoverServer(c *fiber.Ctx) error {
+	var details domain.ConnectionDetails
+	if err := c.BodyParser(&details); err != nil {
+		// The body carries a password, so only the parse error is logged.
+		logger.Log.Warn("Failed to parse discovery request", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	discovery, err := h.service.DiscoverServer(c.UserContext(), details)
+	if err != nil {
+		return serverError(err, "discover server")
+	}
+	return c.Status(fiber.StatusAccepted).JSON(discovery)
+}
+
+// GetServers godoc
+// @Summary List discovered servers
+// @Tags servers
+// @Produce  json
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Success 200 {array} domain.Server
+// @Failure 500 {object} map[string]string
+// @Router /summary/servers [get]
+func (h *serverHandlerImpl) GetServers(c *fiber.Ctx) error {
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(c.Query("pageSize", "10"))
+	if err != nil {
+		pageSize = 10
+	}
+
+	servers, err := h.service.GetServers(page, pageSize)
+	if err != nil {
+		return serverError(err, "get servers")
+	}
+	return c.Status(fiber.StatusOK).JSON(servers)
+}
+
+// GetServer godoc
+// @Summary Get a server and its databases
+// @Description Returns the server with the sources discovered on it
+// @Tags servers
+// @Produce  json
+// @Param id path string true "Server ID"
+// @Success 200 {object} domain.Server
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /summary/servers/{id} [get]
+func (h *serverHandlerImpl) GetServer(c *fiber.Ctx) error {
+	server, err := h.service.GetServer(c.Params("id"))
+	if err != nil {
+		return serverError(err, "get server")
+	}
+	return c.Status(fiber.StatusOK).JSON(server)
+}
+
+// serverError maps service errors to HTTP errors, logging unexpected ones.
+func serverError(err error, action string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidServer):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrServerNotFound):
+		return fiber.NewError(fiber.StatusNotFound, "Server not found")
+	case errors.Is(err, service.ErrServerUnavailable):
+		return fiber.NewError(fiber.StatusServiceUnavailable, err.Error())
+	case errors.Is(err, service.ErrListDatabases):
+		return fiber.NewError(fiber.StatusBadGateway, err.Error())
+	}
+	logger.Log.Error("Server request failed", zap.String("action", action), zap.Error(err))
+	return fiber.NewError(fiber.StatusInternalServerError, "Failed to "+action)
+}
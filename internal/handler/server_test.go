@@ -0,0 +1,78 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/handler"
+	"github.com/lokesh2201013/postgres-data-summary/internal/router"
+	"github.com/lokesh2201013/postgres-data-summary/internal/service"
+)
+
+type mockServerService struct {
+	mock.Mock
+}
+
+func (m *mockServerService) DiscoverServer(ctx context.Context, details domain.ConnectionDetails) (*domain.ServerDiscovery, error) {
+	args := m.Called(details)
+	if d := args.Get(0); d != nil {
+		return d.(*domain.ServerDiscovery), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockServerService) GetServers(page, pageSize int) ([]domain.Server, error) {
+	args := m.Called(page, pageSize)
+	return args.Get(0).([]domain.Server), args.Error(1)
+}
+
+func (m *mockServerService) GetServer(id string) (*domain.Server, error) {
+	args := m.Called(id)
+	if s := args.Get(0); s != nil {
+		return s.(*domain.Server), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func setupServerApp(svc *mockServerService) *fiber.App {
+	app := fiber.New()
+	router.ServerRoutes(app, handler.NewServerHandler(svc))
+	return app
+}
+
+func TestDiscoverServer(t *testing.T) {
+	svc := new(mockServerService)
+	app := setupServerApp(svc)
+
+	svc.On("DiscoverServer", domain.ConnectionDetails{Host: "pg1", User: "dba"}).
+		Return(&domain.ServerDiscovery{Server: domain.Server{ID: "srv-1"}, Databases: []string{"sales"}}, nil)
+	svc.On("DiscoverServer", domain.ConnectionDetails{Host: "down", User: "dba"}).
+		Return(nil, fmt.Errorf("%w: connection refused", service.ErrListDatabases))
+	svc.On("DiscoverServer", domain.ConnectionDetails{Host: "open", User: "dba"}).
+		Return(nil, fmt.Errorf("%w: circuit open", service.ErrServerUnavailable))
+
+	for host, status := range map[string]int{"pg1": http.StatusAccepted, "down": http.StatusBadGateway, "open": http.StatusServiceUnavailable} {
+		body := fmt.Sprintf(`{"host":%q,"user":"dba"}`, host)
+		req := httptest.NewRequest(http.MethodPost, "/summary/servers/discover", bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := app.Test(req)
+		assert.Equal(t, status, resp.StatusCode, host)
+	}
+}
+
+func TestGetServer_NotFound(t *testing.T) {
+	svc := new(mockServerService)
+	svc.On("GetServer", "missing").Return(nil, service.ErrServerNotFound)
+
+	resp, _ := setupServerApp(svc).Test(httptest.NewRequest(http.MethodGet, "/summary/servers/missing", nil))
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
+	"github.com/lokesh2201013/postgres-data-summary/internal/service"
+)
+
+type SourceHandler interface {
+	CreateSource(c *fiber.Ctx) error
+	GetSources(c *fiber.Ctx) error
+	GetSource(c *fiber.Ctx) error
+	UpdateSource(c *fiber.Ctx) error
+	DeleteSource(c *fiber.Ctx) error
+}
+
+type sourceHandlerImpl struct {
+	service service.ISourceService
+}
+
+func NewSourceHandler(service service.ISourceService) SourceHandler {
+	return &sourceHandlerImpl{service: service}
+}
+
+// CreateSource godoc
+// @Summary Save a connection profile
+// @Description Stores host, port, user, dbname, sslmode and an encrypted password so syncs can reference the source by ID. A database already synced without a profile keeps its source ID and snapshot history; 409 is returned only when a profile is already saved for it.
+// @Tags sources
+// @Accept  json
+// @Produce  json
+// @Param details body domain.ConnectionDetails true "Connection profile"
+// @Success 201 {object} domain.Source
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /summary/sources [post]
+func (h *sourceHandlerImpl) CreateSource(c *fiber.Ctx) error {
+	var details domain.ConnectionDetails
+	if err := c.BodyParser(&details); err != nil {
+		logger.Log.Warn("Failed to parse source profile", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	source, err := h.service.CreateSource(details)
+	if err != nil {
+		return sourceError(err, "create source")
+	}
+	return c.Status(fiber.StatusCreated).JSON(source)
+}
+
+// GetSources godoc
+// @Summary List connection profiles
+// @Tags sources
+// @Produce  json
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Success 200 {array} domain.Source
+// @Failure 500 {object} map[string]string
+// @Router /summary/sources [get]
+func (h *sourceHandlerImpl) GetSources(c *fiber.Ctx) error {
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(c.Query("pageSize", "10"))
+	if err != nil {
+		pageSize = 10
+	}
+
+	sources, err := h.service.GetSources(page, pageSize)
+	if err != nil {
+		return sourceError(err, "get sources")
+	}
+	return c.Status(fiber.StatusOK).JSON(sources)
+}
+
+// GetSource godoc
+// @Summary Get a connection profile
+// @Tags sources
+// @Produce  json
+// @Param id path string true "Source ID"
+// @Success 200 {object} domain.Source
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /summary/sources/{id} [get]
+func (h *sourceHandlerImpl) GetSource(c *fiber.Ctx) error {
+	source, err := h.service.GetSource(c.Params("id"))
+	if err != nil {
+		return sourceError(err, "get source")
+	}
+	return c.Status(fiber.StatusOK).JSON(source)
+}
+
+// UpdateSource godoc
+// @Summary Update a connection profile
+// @Description Replaces the connection settings of a source; an empty password keeps the stored one
+// @Tags sources
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Source ID"
+// @Param details body domain.ConnectionDetails true "Connection profile"
+// @Success 200 {object} domain.Source
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /summary/sources/{id} [put]
+func (h *sourceHandlerImpl) UpdateSource(c *fiber.Ctx) error {
+	var details domain.ConnectionDetails
+	if err := c.BodyParser(&details); err != nil {
+		logger.Log.Warn("Failed to parse source profile", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	source, err := h.service.UpdateSource(c.Params("id"), details)
+	if err != nil {
+		return sourceError(err, "update source")
+	}
+	return c.Status(fiber.StatusOK).JSON(source)
+}
+
+// DeleteSource godoc
+// @Summary Delete a connection profile
+// @Description Removes the profile and its stored password; snapshots already taken are kept
+// @Tags sources
+// @Param id path string true "Source ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /summary/sources/{id} [delete]
+func (h *sourceHandlerImpl) DeleteSource(c *fiber.Ctx) error {
+	if err := h.service.DeleteSource(c.Params("id")); err != nil {
+		return sourceError(err, "delete source")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// sourceError maps service errors to HTTP errors, logging unexpected ones.
+func sourceError(err error, action string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidSource), errors.Is(err, service.ErrSecretsDisabled):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrSourceNotFound):
+		return fiber.NewError(fiber.StatusNotFound, "Source not found")
+	case errors.Is(err, service.ErrSourceExists):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	logger.Log.Error("Source request failed", zap.String("action", action), zap.Error(err))
+	return fiber.NewError(fiber.StatusInternalServerError, "Failed to "+action)
+}
@@ -0,0 +1,107 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/handler"
+	"github.com/lokesh2201013/postgres-data-summary/internal/router"
+	"github.com/lokesh2201013/postgres-data-summary/internal/service"
+)
+
+type mockSourceService struct {
+	mock.Mock
+}
+
+func (m *mockSourceService) source(args mock.Arguments) (*domain.Source, error) {
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Source), args.Error(1)
+}
+
+func (m *mockSourceService) CreateSource(details domain.ConnectionDetails) (*domain.Source, error) {
+	return m.source(m.Called(details))
+}
+
+func (m *mockSourceService) GetSources(page, pageSize int) ([]domain.Source, error) {
+	args := m.Called(page, pageSize)
+	return args.Get(0).([]domain.Source), args.Error(1)
+}
+
+func (m *mockSourceService) GetSource(id string) (*domain.Source, error) {
+	return m.source(m.Called(id))
+}
+
+func (m *mockSourceService) UpdateSource(id string, details domain.ConnectionDetails) (*domain.Source, error) {
+	return m.source(m.Called(id, details))
+}
+
+func (m *mockSourceService) DeleteSource(id string) error {
+	return m.Called(id).Error(0)
+}
+
+func (m *mockSourceService) ResolveConnection(id string) (domain.ConnectionDetails, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.ConnectionDetails), args.Error(1)
+}
+
+func setupSourceApp(svc *mockSourceService) *fiber.App {
+	app := fiber.New()
+	router.SourceRoutes(app, handler.NewSourceHandler(svc))
+	return app
+}
+
+func TestCreateSource_HidesCiphertext(t *testing.T) {
+	svc := new(mockSourceService)
+	app := setupSourceApp(svc)
+
+	details := domain.ConnectionDetails{Host: "db", User: "u", Password: "secret", DBName: "demo"}
+	svc.On("CreateSource", details).Return(&domain.Source{ID: "s1", Host: "db", PasswordCiphertext: "c1pher", HasPassword: true}, nil)
+
+	body, _ := json.Marshal(details)
+	req := httptest.NewRequest(http.MethodPost, "/summary/sources", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var raw map[string]interface{}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&raw))
+	assert.Equal(t, true, raw["has_password"])
+	assert.NotContains(t, fmt.Sprint(raw), "c1pher")
+	assert.NotContains(t, fmt.Sprint(raw), "secret")
+}
+
+func TestCreateSource_Conflict(t *testing.T) {
+	svc := new(mockSourceService)
+	app := setupSourceApp(svc)
+
+	svc.On("CreateSource", mock.Anything).Return(nil, service.ErrSourceExists)
+
+	req := httptest.NewRequest(http.MethodPost, "/summary/sources", bytes.NewReader([]byte(`{"host":"db","user":"u","dbname":"demo"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
+
+func TestGetSource_NotFound(t *testing.T) {
+	svc := new(mockSourceService)
+	app := setupSourceApp(svc)
+
+	svc.On("GetSource", "missing").Return(nil, service.ErrSourceNotFound)
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/summary/sources/missing", nil))
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
+	"github.com/lokesh2201013/postgres-data-summary/internal/service"
+)
+
+type StatsHandler interface {
+	GetFleetStats(c *fiber.Ctx) error
+}
+
+type statsHandlerImpl struct {
+	service service.IStatsService
+}
+
+func NewStatsHandler(service service.IStatsService) StatsHandler {
+	return &statsHandlerImpl{service: service}
+}
+
+// GetFleetStats godoc
+// @Summary Get fleet-wide statistics
+// @Description Aggregates the newest snapshot of every source: total databases, schemas, tables, rows and size, the largest tables and databases, and how many databases fall into each size bucket.
+// @Tags stats
+// @Produce  json
+// @Param top query int false "Number of largest tables and databases to list (1-100)" default(10)
+// @Success 200 {object} domain.FleetStats
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /summary/stats [get]
+func (h *statsHandlerImpl) GetFleetStats(c *fiber.Ctx) error {
+	top := c.QueryInt("top", 10)
+
+	stats, err := h.service.GetFleetStats(top)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidQuery) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		logger.Log.Error("Failed to get fleet stats", zap.Error(err))
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get stats")
+	}
+	return c.Status(fiber.StatusOK).JSON(stats)
+}
@@ -0,0 +1,70 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/handler"
+	"github.com/lokesh2201013/postgres-data-summary/internal/router"
+	"github.com/lokesh2201013/postgres-data-summary/internal/service"
+)
+
+type mockStatsService struct {
+	mock.Mock
+}
+
+func (m *mockStatsService) GetFleetStats(top int) (*domain.FleetStats, error) {
+	args := m.Called(top)
+	if s := args.Get(0); s != nil {
+		return s.(*domain.FleetStats), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func setupStatsApp(svc *mockStatsService) *fiber.App {
+	app := fiber.New()
+	router.StatsRoutes(app, handler.NewStatsHandler(svc))
+	return app
+}
+
+func TestGetFleetStats(t *testing.T) {
+	svc := new(mockStatsService)
+	app := setupStatsApp(svc)
+
+	svc.On("GetFleetStats", 10).Return(&domain.FleetStats{Databases: 2, SizeMB: 2108}, nil)
+	svc.On("GetFleetStats", 3).Return(&domain.FleetStats{Databases: 2,
+		LargestTables: []domain.TableStat{{Name: "invoices", DBName: "billing", SizeMB: 2048}}}, nil)
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/summary/stats", nil))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var stats domain.FleetStats
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&stats))
+	assert.Equal(t, 2108.0, stats.SizeMB)
+
+	resp, _ = app.Test(httptest.NewRequest(http.MethodGet, "/summary/stats?top=3", nil))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&stats))
+	assert.Equal(t, "billing", stats.LargestTables[0].DBName)
+}
+
+func TestGetFleetStats_Errors(t *testing.T) {
+	svc := new(mockStatsService)
+	app := setupStatsApp(svc)
+
+	svc.On("GetFleetStats", 0).Return(nil, fmt.Errorf("%w: top must be between 1 and 100, got 0", service.ErrInvalidQuery))
+	svc.On("GetFleetStats", 10).Return(nil, errors.New("db down"))
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/summary/stats?top=0", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp, _ = app.Test(httptest.NewRequest(http.MethodGet, "/summary/stats", nil))
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
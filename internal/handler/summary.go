@@ -2,7 +2,9 @@ package handler
 
 import (
 	//"net/http"
-	"context"
+	"errors"
+	"fmt"
+	"net/url"
 	"strconv"
 	"time"
 
@@ -19,104 +21,223 @@ import (
 
 type SummaryHandler interface {
     SyncSummary(c *fiber.Ctx) error
+    SyncBatch(c *fiber.Ctx) error
     GetSummaries(c *fiber.Ctx) error
     GetSummaryByID(c *fiber.Ctx) error
+    GetSnapshots(c *fiber.Ctx) error
+    GetSnapshot(c *fiber.Ctx) error
+    DiffSummaries(c *fiber.Ctx) error
+    GetJob(c *fiber.Ctx) error
+    DeleteSummary(c *fiber.Ctx) error
+    RestoreSummary(c *fiber.Ctx) error
+    GetSchemas(c *fiber.Ctx) error
+    GetTables(c *fiber.Ctx) error
+    GetTable(c *fiber.Ctx) error
 }
 
 type summaryHandlerImpl struct {
     service service.ISummaryService
+    jobs    service.IJobService
 }
 
-func NewSummaryHandler(service service.ISummaryService) SummaryHandler {
-    return &summaryHandlerImpl{service: service}
+func NewSummaryHandler(service service.ISummaryService, jobs service.IJobService) SummaryHandler {
+    return &summaryHandlerImpl{service: service, jobs: jobs}
 }
 
 
 // SyncSummary godoc
 // @Summary Sync a new database summary
-// @Description Connects to remote PostgreSQL via external API and saves summary
+// @Description Queues a background job that connects to remote PostgreSQL via external API and saves a summary. Send either the ID of a saved source or raw connection details. Poll the returned job for progress.
 // @Tags summary
 // @Accept  json
 // @Produce  json
-// @Param details body domain.ConnectionDetails true "Remote DB connection"
-// @Success 201 {object} domain.Summary
+// @Param request body domain.SyncRequest true "Saved source ID or remote DB connection"
+// @Success 202 {object} domain.SyncJob
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
 // @Router /summary/sync [post]
 func (h *summaryHandlerImpl) SyncSummary(c *fiber.Ctx) error {
-	_, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
 	logger.Log.Info("SyncSummary request received")
 
-	var details domain.ConnectionDetails
-	if err := c.BodyParser(&details); err != nil {
-		logger.Log.Error("Failed to parse ConnectionDetails",
-			zap.Error(err),
-			zap.ByteString("body", c.Body()))
+	var req domain.SyncRequest
+	if err := c.BodyParser(&req); err != nil {
+		// The body may carry a password, so only the parse error is logged.
+		logger.Log.Error("Failed to parse SyncRequest", zap.Error(err))
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	if details.Host == "" || details.Port == nil || details.User == "" || details.DBName == "" {
+	details := req.ConnectionDetails
+	if err := service.ValidateSyncRequest(req); err != nil {
 		logger.Log.Warn("Missing required connection details",
-			zap.Any("details", details))
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Missing required connection details"})
+			zap.String("host", details.Host),
+			zap.String("dbname", details.DBName))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	summary, err := h.service.UpdateSummary(details)
+	job, err := h.jobs.EnqueueSync(req)
 	if err != nil {
-		logger.Log.Error("UpdateSummary failed",
-			zap.Any("details", details),
+		switch {
+		case errors.Is(err, service.ErrSourceNotFound):
+			return fiber.NewError(fiber.StatusNotFound, "Source not found")
+		case errors.Is(err, service.ErrQueueFull):
+			logger.Log.Warn("Sync queue is full", zap.String("sourceID", req.SourceID), zap.String("host", details.Host), zap.String("dbname", details.DBName))
+			return fiber.NewError(fiber.StatusServiceUnavailable, "Sync queue is full, retry later")
+		}
+		logger.Log.Error("EnqueueSync failed",
+			zap.String("sourceID", req.SourceID),
+			zap.String("host", details.Host),
+			zap.String("dbname", details.DBName),
 			zap.Error(err))
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to sync summary")
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to queue sync")
 	}
 
-	// x := local.NewSummaryRepository()
-	// if err := x.SaveSummary(summary); err != nil {
-	// 	logger.Log.Error("SaveSummary failed",
-	// 		zap.Any("summary", summary),
-	// 		zap.Error(err))
-	// 	return fiber.NewError(fiber.StatusInternalServerError, "Failed to save summary")
-	// }
-
-	logger.Log.Info("Summary synced successfully", zap.String("id", summary.ID))
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"message": "Summary synced successfully",
-		"summary": summary,
+	logger.Log.Info("Sync job queued", zap.String("jobID", job.ID))
+	c.Location("/summary/jobs/" + job.ID)
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "Sync job queued",
+		"job":     job,
 	})
 }
 
 
+// SyncBatch godoc
+// @Summary Sync many databases in one call
+// @Description Queues a background sync job for every item, each either a saved source ID or raw connection details, and returns without waiting for them. The response lists each item's job ID, or why it was rejected, in request order; poll GET /summary/jobs/{id} for progress. A rejected item does not fail the batch.
+// @Tags summary
+// @Accept  json
+// @Produce  json
+// @Param requests body []domain.SyncRequest true "Saved source IDs or remote DB connections"
+// @Success 202 {object} domain.BatchSyncResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /summary/sync/batch [post]
+func (h *summaryHandlerImpl) SyncBatch(c *fiber.Ctx) error {
+	var reqs []domain.SyncRequest
+	if err := c.BodyParser(&reqs); err != nil {
+		// The body may carry passwords, so only the parse error is logged.
+		logger.Log.Error("Failed to parse batch sync request", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	response, err := h.jobs.SyncBatch(reqs)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidBatch) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		logger.Log.Error("SyncBatch failed", zap.Int("items", len(reqs)), zap.Error(err))
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to sync batch")
+	}
+	return c.Status(fiber.StatusAccepted).JSON(response)
+}
+
+
 // GetSummaries godoc
 // @Summary Get all summaries
-// @Description Retrieves paginated summaries
+// @Description Retrieves a page of summaries with their schema count, table count and total size, optionally filtered by target, sync time, table name or total size, searched by schema or table name, and sorted
 // @Tags summary
 // @Produce  json
 // @Param page query int false "Page number"
 // @Param pageSize query int false "Page size"
-// @Success 200 {array} domain.Summary
+// @Param host query string false "Only summaries of this host"
+// @Param dbname query string false "Only summaries of this database"
+// @Param syncedAfter query string false "Only summaries synced at or after this RFC 3339 time"
+// @Param syncedBefore query string false "Only summaries synced before this RFC 3339 time"
+// @Param hasTable query string false "Only summaries containing a table with this exact name"
+// @Param minSizeMb query number false "Only summaries whose tables total at least this many MB"
+// @Param q query string false "Case-insensitive substring of a schema or table name"
+// @Param sort query string false "Sort key" Enums(synced_at, total_size, table_count) default(synced_at)
+// @Param order query string false "Sort direction" Enums(asc, desc) default(desc)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param view query string false "compact leaves out the schemas and tables" Enums(full, compact) default(full)
+// @Success 200 {object} domain.SummaryPage
+// @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /summary/summaries [get]
 func (h *summaryHandlerImpl) GetSummaries(c *fiber.Ctx) error {
 	page, err := strconv.Atoi(c.Query("page", "1"))
-	if err != nil {
+	if err != nil || page < 1 {
 		page = 1
 	}
 	pageSize, err := strconv.Atoi(c.Query("pageSize", "10"))
 	if err != nil {
 		pageSize = 10
 	}
+	query, err := summaryQuery(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if pageSize < 1 {
+		pageSize = 10
+	}
 
 	logger.Log.Info("GetSummaries request received", zap.Int("page", page), zap.Int("pageSize", pageSize))
 
-	summaries, err := h.service.GetSummaries(page, pageSize)
+	result, err := h.service.GetSummaries(query, page, pageSize)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidQuery) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
 		logger.Log.Error("GetSummaries failed", zap.Error(err))
 		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get summaries")
 	}
 
-	logger.Log.Info("GetSummaries succeeded", zap.Int("count", len(summaries)))
-	return c.Status(fiber.StatusOK).JSON(summaries)
+	logger.Log.Info("GetSummaries succeeded", zap.Int("count", len(result.Items)))
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// summaryQuery parses the filter, search and sort parameters of the
+// summaries list.
+func summaryQuery(c *fiber.Ctx) (domain.SummaryQuery, error) {
+	query := domain.SummaryQuery{
+		Host:     c.Query("host"),
+		DBName:   c.Query("dbname"),
+		HasTable: c.Query("hasTable"),
+		Q:        c.Query("q"),
+		Sort:     c.Query("sort"),
+	}
+
+	for name, dst := range map[string]**time.Time{"syncedAfter": &query.SyncedAfter, "syncedBefore": &query.SyncedBefore} {
+		if raw := c.Query(name); raw != "" {
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return query, fmt.Errorf("%s must be an RFC 3339 time, got %q", name, raw)
+			}
+			*dst = &t
+		}
+	}
+	if raw := c.Query("minSizeMb"); raw != "" {
+		size, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return query, fmt.Errorf("minSizeMb must be a number, got %q", raw)
+		}
+		query.MinSizeMB = size
+	}
+
+	switch order := c.Query("order", "desc"); order {
+	case "desc":
+		query.Desc = true
+	case "asc":
+	default:
+		return query, fmt.Errorf(`order must be "asc" or "desc", got %q`, order)
+	}
+	switch view := c.Query("view", "full"); view {
+	case "compact":
+		query.Compact = true
+	case "full":
+	default:
+		return query, fmt.Errorf(`view must be "full" or "compact", got %q`, view)
+	}
+	if raw := c.Query("cursor"); raw != "" {
+		cursor, err := domain.ParseSummaryCursor(raw)
+		if err != nil {
+			return query, err
+		}
+		query.After = cursor
+	}
+	return query, nil
 }
 
 
@@ -147,4 +268,326 @@ func (h *summaryHandlerImpl) GetSummaryByID(c *fiber.Ctx) error {
 
 	logger.Log.Info("GetSummaryByID succeeded", zap.String("id", summary.ID))
 	return c.Status(fiber.StatusOK).JSON(summary)
-}
\ No newline at end of file
+}
+
+
+// GetSnapshots godoc
+// @Summary List snapshots of a source database
+// @Description Retrieves the paginated snapshot history of a source, newest first, without the schema tree
+// @Tags summary
+// @Produce  json
+// @Param id path string true "Source ID"
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Success 200 {array} domain.Summary
+// @Failure 500 {object} map[string]string
+// @Router /summary/sources/{id}/snapshots [get]
+func (h *summaryHandlerImpl) GetSnapshots(c *fiber.Ctx) error {
+	sourceID := c.Params("id")
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(c.Query("pageSize", "10"))
+	if err != nil {
+		pageSize = 10
+	}
+
+	logger.Log.Info("GetSnapshots request received", zap.String("sourceID", sourceID), zap.Int("page", page), zap.Int("pageSize", pageSize))
+
+	snapshots, err := h.service.GetSnapshots(sourceID, page, pageSize)
+	if err != nil {
+		logger.Log.Error("GetSnapshots failed", zap.String("sourceID", sourceID), zap.Error(err))
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get snapshots")
+	}
+
+	logger.Log.Info("GetSnapshots succeeded", zap.String("sourceID", sourceID), zap.Int("count", len(snapshots)))
+	return c.Status(fiber.StatusOK).JSON(snapshots)
+}
+
+
+// GetSnapshot godoc
+// @Summary Get a historical snapshot
+// @Description Retrieves a full snapshot of a source database by snapshot ID
+// @Tags summary
+// @Produce  json
+// @Param id path string true "Source ID"
+// @Param snapshotId path string true "Snapshot (summary) ID"
+// @Success 200 {object} domain.Summary
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /summary/sources/{id}/snapshots/{snapshotId} [get]
+func (h *summaryHandlerImpl) GetSnapshot(c *fiber.Ctx) error {
+	sourceID := c.Params("id")
+	id := c.Params("snapshotId")
+	logger.Log.Info("GetSnapshot request received", zap.String("sourceID", sourceID), zap.String("id", id))
+
+	snapshot, err := h.service.GetSnapshot(sourceID, id)
+	if err != nil {
+		logger.Log.Error("GetSnapshot failed", zap.String("sourceID", sourceID), zap.String("id", id), zap.Error(err))
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get snapshot")
+	}
+
+	if snapshot == nil {
+		logger.Log.Warn("Snapshot not found", zap.String("sourceID", sourceID), zap.String("id", id))
+		return fiber.NewError(fiber.StatusNotFound, "Snapshot not found")
+	}
+
+	logger.Log.Info("GetSnapshot succeeded", zap.String("id", snapshot.ID))
+	return c.Status(fiber.StatusOK).JSON(snapshot)
+}
+
+
+// DiffSummaries godoc
+// @Summary Diff two summaries
+// @Description Compares two stored summaries and reports added, removed and renamed schemas and tables, row count changes and size deltas
+// @Tags summary
+// @Produce  json
+// @Param from query string true "ID of the older summary"
+// @Param to query string true "ID of the newer summary"
+// @Success 200 {object} domain.SummaryDiff
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /summary/diff [get]
+func (h *summaryHandlerImpl) DiffSummaries(c *fiber.Ctx) error {
+	fromID := c.Query("from")
+	toID := c.Query("to")
+	if fromID == "" || toID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "Both from and to summary IDs are required")
+	}
+
+	logger.Log.Info("DiffSummaries request received", zap.String("from", fromID), zap.String("to", toID))
+
+	diff, err := h.service.DiffSummaries(fromID, toID)
+	if err != nil {
+		if errors.Is(err, service.ErrSummaryNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		}
+		logger.Log.Error("DiffSummaries failed", zap.String("from", fromID), zap.String("to", toID), zap.Error(err))
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to diff summaries")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(diff)
+}
+
+
+// GetJob godoc
+// @Summary Get sync job status
+// @Description Retrieves the status, attempt count, timings and resulting summary ID of a sync job
+// @Tags summary
+// @Produce  json
+// @Param id path string true "Job ID"
+// @Success 200 {object} domain.SyncJob
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /summary/jobs/{id} [get]
+func (h *summaryHandlerImpl) GetJob(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	job, err := h.jobs.GetJob(id)
+	if err != nil {
+		logger.Log.Error("GetJob failed", zap.String("jobID", id), zap.Error(err))
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get job")
+	}
+	if job == nil {
+		return fiber.NewError(fiber.StatusNotFound, "Job not found")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(job)
+}
+
+
+// DeleteSummary godoc
+// @Summary Delete a summary
+// @Description Soft-deletes a summary so it no longer appears in listings, snapshots or diffs; it can be restored until it is purged. With purge=true the summary, deleted or not, is removed permanently together with its schemas, tables, columns, indexes and constraints.
+// @Tags summary
+// @Param id path string true "Summary ID"
+// @Param purge query bool false "Remove permanently instead of soft-deleting"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /summary/summaries/{id} [delete]
+func (h *summaryHandlerImpl) DeleteSummary(c *fiber.Ctx) error {
+	id := c.Params("id")
+	purge := c.QueryBool("purge")
+	logger.Log.Info("DeleteSummary request received", zap.String("id", id), zap.Bool("purge", purge))
+
+	var err error
+	if purge {
+		err = h.service.PurgeSummary(id)
+	} else {
+		err = h.service.DeleteSummary(id)
+	}
+	if err != nil {
+		if errors.Is(err, service.ErrSummaryNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, "Summary not found")
+		}
+		logger.Log.Error("DeleteSummary failed", zap.String("id", id), zap.Bool("purge", purge), zap.Error(err))
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to delete summary")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+
+// RestoreSummary godoc
+// @Summary Restore a deleted summary
+// @Description Undoes a soft delete. Purged summaries cannot be restored.
+// @Tags summary
+// @Produce  json
+// @Param id path string true "Summary ID"
+// @Success 200 {object} domain.Summary
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /summary/summaries/{id}/restore [post]
+func (h *summaryHandlerImpl) RestoreSummary(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	summary, err := h.service.RestoreSummary(id)
+	if err != nil {
+		if errors.Is(err, service.ErrSummaryNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, "Deleted summary not found")
+		}
+		logger.Log.Error("RestoreSummary failed", zap.String("id", id), zap.Error(err))
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to restore summary")
+	}
+	return c.Status(fiber.StatusOK).JSON(summary)
+}
+
+
+// GetSchemas godoc
+// @Summary List the schemas of a summary
+// @Description Retrieves a page of the schemas of a summary with their table count and total size, without loading any tables
+// @Tags summary
+// @Produce  json
+// @Param id path string true "Summary ID"
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Param sort query string false "Sort key" Enums(name, table_count, total_size) default(name)
+// @Param order query string false "Sort direction" Enums(asc, desc) default(asc)
+// @Success 200 {object} domain.SchemaPage
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /summary/summaries/{id}/schemas [get]
+func (h *summaryHandlerImpl) GetSchemas(c *fiber.Ctx) error {
+	id := c.Params("id")
+	order, err := listOrder(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	page, pageSize := pageParams(c)
+
+	schemas, err := h.service.GetSchemas(id, order, page, pageSize)
+	if err != nil {
+		return treeError(err, "get schemas")
+	}
+	return c.Status(fiber.StatusOK).JSON(schemas)
+}
+
+
+// GetTables godoc
+// @Summary List the tables of a schema
+// @Description Retrieves a page of the tables of one schema of a summary, without their columns, indexes and constraints
+// @Tags summary
+// @Produce  json
+// @Param id path string true "Summary ID"
+// @Param schema path string true "Schema name"
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Param sort query string false "Sort key" Enums(name, row_count, size_mb) default(name)
+// @Param order query string false "Sort direction" Enums(asc, desc) default(asc)
+// @Success 200 {object} domain.TablePage
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /summary/summaries/{id}/schemas/{schema}/tables [get]
+func (h *summaryHandlerImpl) GetTables(c *fiber.Ctx) error {
+	id := c.Params("id")
+	schema, err := url.PathUnescape(c.Params("schema"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid schema name")
+	}
+	order, err := listOrder(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	page, pageSize := pageParams(c)
+
+	tables, err := h.service.GetTables(id, schema, order, page, pageSize)
+	if err != nil {
+		return treeError(err, "get tables")
+	}
+	return c.Status(fiber.StatusOK).JSON(tables)
+}
+
+
+// GetTable godoc
+// @Summary Get a table of a summary
+// @Description Retrieves one table with its columns, indexes and constraints
+// @Tags summary
+// @Produce  json
+// @Param id path string true "Summary ID"
+// @Param schema path string true "Schema name"
+// @Param table path string true "Table name"
+// @Success 200 {object} domain.Table
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /summary/summaries/{id}/schemas/{schema}/tables/{table} [get]
+func (h *summaryHandlerImpl) GetTable(c *fiber.Ctx) error {
+	id := c.Params("id")
+	schema, err := url.PathUnescape(c.Params("schema"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid schema name")
+	}
+	name, err := url.PathUnescape(c.Params("table"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid table name")
+	}
+
+	table, err := h.service.GetTable(id, schema, name)
+	if err != nil {
+		return treeError(err, "get table")
+	}
+	return c.Status(fiber.StatusOK).JSON(table)
+}
+
+// pageParams parses page and pageSize, falling back to the first page of 10.
+func pageParams(c *fiber.Ctx) (int, int) {
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(c.Query("pageSize", "10"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10
+	}
+	return page, pageSize
+}
+
+// listOrder parses the sort and order parameters of a sub-resource list.
+func listOrder(c *fiber.Ctx) (domain.ListOrder, error) {
+	order := domain.ListOrder{Sort: c.Query("sort")}
+	switch direction := c.Query("order", "asc"); direction {
+	case "asc":
+	case "desc":
+		order.Desc = true
+	default:
+		return order, fmt.Errorf(`order must be "asc" or "desc", got %q`, direction)
+	}
+	return order, nil
+}
+
+// treeError maps errors of the schema and table endpoints to HTTP errors,
+// logging unexpected ones.
+func treeError(err error, action string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidQuery):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrSummaryNotFound), errors.Is(err, service.ErrSchemaNotFound), errors.Is(err, service.ErrTableNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	logger.Log.Error("Summary tree request failed", zap.String("action", action), zap.Error(err))
+	return fiber.NewError(fiber.StatusInternalServerError, "Failed to "+action)
+}
@@ -2,8 +2,10 @@ package handler_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -19,6 +21,7 @@ import (
 	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
 	"github.com/lokesh2201013/postgres-data-summary/internal/handler"
 	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
+	"github.com/lokesh2201013/postgres-data-summary/internal/service"
 )
 
 type mockSummaryService struct {
@@ -36,7 +39,7 @@ func init() {
     )
     logger.Log = zap.New(core)
 }
-func (m *mockSummaryService) UpdateSummary(details domain.ConnectionDetails) (*domain.Summary, error) {
+func (m *mockSummaryService) UpdateSummary(ctx context.Context, details domain.ConnectionDetails) (*domain.Summary, error) {
 	args := m.Called(details)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -44,9 +47,12 @@ func (m *mockSummaryService) UpdateSummary(details domain.ConnectionDetails) (*d
 	return args.Get(0).(*domain.Summary), args.Error(1)
 }
 
-func (m *mockSummaryService) GetSummaries(page, pageSize int) ([]domain.Summary, error) {
-	args := m.Called(page, pageSize)
-	return args.Get(0).([]domain.Summary), args.Error(1)
+func (m *mockSummaryService) GetSummaries(query domain.SummaryQuery, page, pageSize int) (*domain.SummaryPage, error) {
+	args := m.Called(query, page, pageSize)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SummaryPage), args.Error(1)
 }
 
 func (m *mockSummaryService) GetSummaryByID(id string) (*domain.Summary, error) {
@@ -57,34 +63,140 @@ func (m *mockSummaryService) GetSummaryByID(id string) (*domain.Summary, error)
 	return args.Get(0).(*domain.Summary), args.Error(1)
 }
 
+func (m *mockSummaryService) GetSnapshots(sourceID string, page, pageSize int) ([]domain.Summary, error) {
+	args := m.Called(sourceID, page, pageSize)
+	return args.Get(0).([]domain.Summary), args.Error(1)
+}
+
+func (m *mockSummaryService) GetSnapshot(sourceID, id string) (*domain.Summary, error) {
+	args := m.Called(sourceID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Summary), args.Error(1)
+}
+
+func (m *mockSummaryService) DiffSummaries(fromID, toID string) (*domain.SummaryDiff, error) {
+	args := m.Called(fromID, toID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SummaryDiff), args.Error(1)
+}
+
+func (m *mockSummaryService) DeleteSummary(id string) error {
+	return m.Called(id).Error(0)
+}
+
+func (m *mockSummaryService) RestoreSummary(id string) (*domain.Summary, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Summary), args.Error(1)
+}
+
+func (m *mockSummaryService) PurgeSummary(id string) error {
+	return m.Called(id).Error(0)
+}
+
+func (m *mockSummaryService) GetSchemas(summaryID string, order domain.ListOrder, page, pageSize int) (*domain.SchemaPage, error) {
+	args := m.Called(summaryID, order, page, pageSize)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SchemaPage), args.Error(1)
+}
+
+func (m *mockSummaryService) GetTables(summaryID, schemaName string, order domain.ListOrder, page, pageSize int) (*domain.TablePage, error) {
+	args := m.Called(summaryID, schemaName, order, page, pageSize)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TablePage), args.Error(1)
+}
+
+func (m *mockSummaryService) GetTable(summaryID, schemaName, tableName string) (*domain.Table, error) {
+	args := m.Called(summaryID, schemaName, tableName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Table), args.Error(1)
+}
+
+type mockJobService struct {
+	mock.Mock
+}
+
+func (m *mockJobService) EnqueueSync(req domain.SyncRequest) (*domain.SyncJob, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SyncJob), args.Error(1)
+}
+
+func (m *mockJobService) SyncBatch(reqs []domain.SyncRequest) (*domain.BatchSyncResponse, error) {
+	args := m.Called(reqs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BatchSyncResponse), args.Error(1)
+}
+
+func (m *mockJobService) GetJob(id string) (*domain.SyncJob, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SyncJob), args.Error(1)
+}
+
 // ---- Test Setup ----
 func setupApp(svc *mockSummaryService) *fiber.App {
-	h := handler.NewSummaryHandler(svc)
+	return setupAppWithJobs(svc, new(mockJobService))
+}
+
+func setupAppWithJobs(svc *mockSummaryService, jobs *mockJobService) *fiber.App {
+	h := handler.NewSummaryHandler(svc, jobs)
 	app := fiber.New()
 	api := app.Group("/summary")
 	api.Post("/sync", h.SyncSummary)
+	api.Post("/sync/batch", h.SyncBatch)
 	api.Get("/summaries", h.GetSummaries)
 	api.Get("/summaries/:id", h.GetSummaryByID)
+	api.Delete("/summaries/:id", h.DeleteSummary)
+	api.Post("/summaries/:id/restore", h.RestoreSummary)
+	api.Get("/summaries/:id/schemas", h.GetSchemas)
+	api.Get("/summaries/:id/schemas/:schema/tables", h.GetTables)
+	api.Get("/summaries/:id/schemas/:schema/tables/:table", h.GetTable)
+	api.Get("/sources/:id/snapshots", h.GetSnapshots)
+	api.Get("/sources/:id/snapshots/:snapshotId", h.GetSnapshot)
+	api.Get("/diff", h.DiffSummaries)
+	api.Get("/jobs/:id", h.GetJob)
 	return app
 }
 
 // ---- Tests ----
 func TestSyncSummary_Success(t *testing.T) {
 	svc := new(mockSummaryService)
-	app := setupApp(svc)
+	jobs := new(mockJobService)
+	app := setupAppWithJobs(svc, jobs)
 
 	port := 5432
 	details := domain.ConnectionDetails{
 		Host: "localhost", Port: &port, User: "test", DBName: "demo",
 	}
 
-	expected := &domain.Summary{
-		ID:       "123",
-		SyncedAt: time.Now(),
-		SourceInfo: details,
+	expected := &domain.SyncJob{
+		ID:        "job-1",
+		Status:    domain.JobQueued,
+		Host:      details.Host,
+		DBName:    details.DBName,
+		CreatedAt: time.Now(),
 	}
 
-	svc.On("UpdateSummary", details).Return(expected, nil)
+	jobs.On("EnqueueSync", domain.SyncRequest{ConnectionDetails: details}).Return(expected, nil)
 
 	body, _ := json.Marshal(details)
 	req := httptest.NewRequest(http.MethodPost, "/summary/sync", bytes.NewReader(body))
@@ -92,7 +204,66 @@ func TestSyncSummary_Success(t *testing.T) {
 
 	resp, _ := app.Test(req)
 
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	assert.Equal(t, "/summary/jobs/job-1", resp.Header.Get("Location"))
+	svc.AssertNotCalled(t, "UpdateSummary", mock.Anything)
+}
+
+func TestSyncSummary_DefaultsPort(t *testing.T) {
+	svc := new(mockSummaryService)
+	jobs := new(mockJobService)
+	app := setupAppWithJobs(svc, jobs)
+
+	details := domain.ConnectionDetails{Host: "localhost", User: "test", DBName: "demo"}
+	jobs.On("EnqueueSync", domain.SyncRequest{ConnectionDetails: details}).Return(&domain.SyncJob{ID: "job-1", Status: domain.JobQueued}, nil)
+
+	body, _ := json.Marshal(details)
+	req := httptest.NewRequest(http.MethodPost, "/summary/sync", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	req = httptest.NewRequest(http.MethodPost, "/summary/sync", bytes.NewReader([]byte(`{"host":"localhost","dbname":"demo"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ = app.Test(req)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestSyncSummary_QueueFull(t *testing.T) {
+	svc := new(mockSummaryService)
+	jobs := new(mockJobService)
+	app := setupAppWithJobs(svc, jobs)
+
+	port := 5432
+	details := domain.ConnectionDetails{Host: "localhost", Port: &port, User: "test", DBName: "demo"}
+	jobs.On("EnqueueSync", domain.SyncRequest{ConnectionDetails: details}).Return(nil, service.ErrQueueFull)
+
+	body, _ := json.Marshal(details)
+	req := httptest.NewRequest(http.MethodPost, "/summary/sync", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+func TestSyncSummary_SourceID(t *testing.T) {
+	svc := new(mockSummaryService)
+	jobs := new(mockJobService)
+	app := setupAppWithJobs(svc, jobs)
+
+	jobs.On("EnqueueSync", domain.SyncRequest{SourceID: "src-1"}).Return(&domain.SyncJob{ID: "job-2", Status: domain.JobQueued}, nil)
+	jobs.On("EnqueueSync", domain.SyncRequest{SourceID: "missing"}).Return(nil, service.ErrSourceNotFound)
+
+	req := httptest.NewRequest(http.MethodPost, "/summary/sync", bytes.NewReader([]byte(`{"source_id":"src-1"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	req = httptest.NewRequest(http.MethodPost, "/summary/sync", bytes.NewReader([]byte(`{"source_id":"missing"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ = app.Test(req)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
 func TestSyncSummary_InvalidBody(t *testing.T) {
@@ -106,21 +277,127 @@ func TestSyncSummary_InvalidBody(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
+func TestSyncBatch(t *testing.T) {
+	jobs := new(mockJobService)
+	app := setupAppWithJobs(new(mockSummaryService), jobs)
+
+	reqs := []domain.SyncRequest{
+		{SourceID: "src-1"},
+		{ConnectionDetails: domain.ConnectionDetails{Host: "db2", User: "u", DBName: "demo"}},
+	}
+	jobs.On("SyncBatch", reqs).Return(&domain.BatchSyncResponse{
+		Queued: 1,
+		Failed: 1,
+		Results: []domain.BatchSyncResult{
+			{Index: 0, SourceID: "src-1", Host: "db1", DBName: "demo", JobID: "job-1", Status: domain.JobQueued},
+			{Index: 1, Host: "db2", DBName: "demo", Status: domain.JobFailed, Error: "sync queue is full"},
+		},
+	}, nil)
+	jobs.On("SyncBatch", []domain.SyncRequest{}).Return(nil, fmt.Errorf("%w: empty", service.ErrInvalidBatch))
+
+	body, _ := json.Marshal(reqs)
+	req := httptest.NewRequest(http.MethodPost, "/summary/sync/batch", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	var result domain.BatchSyncResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, 1, result.Queued)
+	assert.Equal(t, "job-1", result.Results[0].JobID)
+	assert.Equal(t, "sync queue is full", result.Results[1].Error)
+
+	req = httptest.NewRequest(http.MethodPost, "/summary/sync/batch", bytes.NewReader([]byte(`[]`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ = app.Test(req)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
 func TestGetSummaries_Success(t *testing.T) {
 	svc := new(mockSummaryService)
 	app := setupApp(svc)
 
-	expected := []domain.Summary{
-		{ID: "1", SyncedAt: time.Now()},
-		{ID: "2", SyncedAt: time.Now()},
+	expected := &domain.SummaryPage{
+		Items: []domain.SummaryListItem{
+			{ID: "1", SyncedAt: time.Now(), TableCount: 3},
+			{ID: "2", SyncedAt: time.Now()},
+		},
+		NextCursor: "next",
+		Total:      7,
 	}
 
-	svc.On("GetSummaries", 1, 10).Return(expected, nil)
+	svc.On("GetSummaries", domain.SummaryQuery{Desc: true}, 1, 10).Return(expected, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/summary/summaries?page=1&pageSize=10", nil)
 	resp, _ := app.Test(req)
 
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var body map[string]interface{}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "next", body["next_cursor"])
+	assert.Equal(t, float64(7), body["total"])
+	assert.Len(t, body["items"], 2)
+}
+
+func TestGetSummaries_CursorAndView(t *testing.T) {
+	svc := new(mockSummaryService)
+	app := setupApp(svc)
+
+	cursor := domain.SummaryCursor{Sort: domain.SortTableCount, Value: "4", ID: "sum-9"}
+	svc.On("GetSummaries", domain.SummaryQuery{Sort: domain.SortTableCount, After: &cursor, Compact: true}, 1, 25).
+		Return(&domain.SummaryPage{Items: []domain.SummaryListItem{}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/summary/summaries?pageSize=25&sort=table_count&order=asc&view=compact&cursor="+cursor.Encode(), nil)
+	resp, _ := app.Test(req)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	svc.AssertExpectations(t)
+
+	bad := domain.SummaryCursor{Sort: domain.SortTotalSize, Value: "1e400", ID: "sum-9"}
+	for _, params := range []string{"cursor=not-a-cursor", "cursor=" + bad.Encode(), "view=tiny"} {
+		resp, _ = app.Test(httptest.NewRequest(http.MethodGet, "/summary/summaries?"+params, nil))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, params)
+	}
+}
+
+func TestGetSummaries_ClampsPage(t *testing.T) {
+	svc := new(mockSummaryService)
+	app := setupApp(svc)
+
+	svc.On("GetSummaries", domain.SummaryQuery{Desc: true}, 1, 10).Return(&domain.SummaryPage{Items: []domain.SummaryListItem{}}, nil)
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/summary/summaries?page=-2", nil))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	svc.AssertExpectations(t)
+}
+
+func TestGetSummaries_Filters(t *testing.T) {
+	svc := new(mockSummaryService)
+	app := setupApp(svc)
+
+	after := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc.On("GetSummaries", domain.SummaryQuery{
+		Host:        "pg1",
+		DBName:      "sales",
+		SyncedAfter: &after,
+		HasTable:    "orders",
+		MinSizeMB:   12.5,
+		Q:           "ord",
+		Sort:        domain.SortTotalSize,
+	}, 2, 5).Return(&domain.SummaryPage{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/summary/summaries?page=2&pageSize=5&host=pg1&dbname=sales&syncedAfter=2025-01-01T00:00:00Z&hasTable=orders&minSizeMb=12.5&q=ord&sort=total_size&order=asc", nil)
+	resp, _ := app.Test(req)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	svc.AssertExpectations(t)
+
+	for _, params := range []string{"syncedBefore=yesterday", "minSizeMb=big", "order=up"} {
+		resp, _ = app.Test(httptest.NewRequest(http.MethodGet, "/summary/summaries?"+params, nil))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, params)
+	}
+
+	svc.On("GetSummaries", domain.SummaryQuery{Sort: "name", Desc: true}, 1, 10).Return(nil, fmt.Errorf("%w: bad sort", service.ErrInvalidQuery))
+	resp, _ = app.Test(httptest.NewRequest(http.MethodGet, "/summary/summaries?sort=name", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
 func TestGetSummaryByID_NotFound(t *testing.T) {
@@ -146,3 +423,215 @@ func TestGetSummaryByID_Error(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 }
+
+func TestGetSnapshots_Success(t *testing.T) {
+	svc := new(mockSummaryService)
+	app := setupApp(svc)
+
+	expected := []domain.Summary{
+		{ID: "2", SourceID: "src", SyncedAt: time.Now()},
+		{ID: "1", SourceID: "src", SyncedAt: time.Now().Add(-time.Hour)},
+	}
+
+	svc.On("GetSnapshots", "src", 1, 10).Return(expected, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/summary/sources/src/snapshots", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestGetSnapshot_NotFound(t *testing.T) {
+	svc := new(mockSummaryService)
+	app := setupApp(svc)
+
+	svc.On("GetSnapshot", "src", "missing").Return(nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/summary/sources/src/snapshots/missing", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestDiffSummaries_MissingParams(t *testing.T) {
+	svc := new(mockSummaryService)
+	app := setupApp(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/summary/diff?from=a", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestDiffSummaries_NotFound(t *testing.T) {
+	svc := new(mockSummaryService)
+	app := setupApp(svc)
+
+	svc.On("DiffSummaries", "a", "b").Return(nil, fmt.Errorf("%w: b", service.ErrSummaryNotFound))
+
+	req := httptest.NewRequest(http.MethodGet, "/summary/diff?from=a&to=b", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestDiffSummaries_Success(t *testing.T) {
+	svc := new(mockSummaryService)
+	app := setupApp(svc)
+
+	svc.On("DiffSummaries", "a", "b").Return(&domain.SummaryDiff{FromID: "a", ToID: "b", AddedSchemas: []string{"sales"}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/summary/diff?from=a&to=b", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var diff domain.SummaryDiff
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&diff))
+	assert.Equal(t, []string{"sales"}, diff.AddedSchemas)
+}
+
+func TestGetJob_Success(t *testing.T) {
+	svc := new(mockSummaryService)
+	jobs := new(mockJobService)
+	app := setupAppWithJobs(svc, jobs)
+
+	summaryID := "sum-1"
+	jobs.On("GetJob", "job-1").Return(&domain.SyncJob{ID: "job-1", Status: domain.JobSucceeded, Attempts: 1, SummaryID: &summaryID}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/summary/jobs/job-1", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var job domain.SyncJob
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&job))
+	assert.Equal(t, domain.JobSucceeded, job.Status)
+	assert.Equal(t, "sum-1", *job.SummaryID)
+}
+
+func TestGetJob_NotFound(t *testing.T) {
+	svc := new(mockSummaryService)
+	jobs := new(mockJobService)
+	app := setupAppWithJobs(svc, jobs)
+
+	jobs.On("GetJob", "missing").Return(nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/summary/jobs/missing", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestGetSummaryByID_OmitsPassword(t *testing.T) {
+	svc := new(mockSummaryService)
+	app := setupApp(svc)
+
+	port := 5432
+	svc.On("GetSummaryByID", "1").Return(&domain.Summary{
+		ID:         "1",
+		SourceInfo: domain.ConnectionDetails{Host: "db", Port: &port, User: "u", DBName: "demo"},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/summary/summaries/1", nil)
+	resp, _ := app.Test(req)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		SourceInfo map[string]interface{} `json:"source_info"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "db", body.SourceInfo["host"])
+	assert.NotContains(t, body.SourceInfo, "password")
+}
+
+func TestDeleteSummary(t *testing.T) {
+	svc := new(mockSummaryService)
+	app := setupApp(svc)
+
+	svc.On("DeleteSummary", "sum-1").Return(nil)
+	svc.On("PurgeSummary", "sum-2").Return(nil)
+	svc.On("DeleteSummary", "missing").Return(service.ErrSummaryNotFound)
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodDelete, "/summary/summaries/sum-1", nil))
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp, _ = app.Test(httptest.NewRequest(http.MethodDelete, "/summary/summaries/sum-2?purge=true", nil))
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp, _ = app.Test(httptest.NewRequest(http.MethodDelete, "/summary/summaries/missing", nil))
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	svc.AssertExpectations(t)
+}
+
+func TestRestoreSummary(t *testing.T) {
+	svc := new(mockSummaryService)
+	app := setupApp(svc)
+
+	svc.On("RestoreSummary", "sum-1").Return(&domain.Summary{ID: "sum-1"}, nil)
+	svc.On("RestoreSummary", "missing").Return(nil, service.ErrSummaryNotFound)
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodPost, "/summary/summaries/sum-1/restore", nil))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var restored domain.Summary
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&restored))
+	assert.Equal(t, "sum-1", restored.ID)
+
+	resp, _ = app.Test(httptest.NewRequest(http.MethodPost, "/summary/summaries/missing/restore", nil))
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestGetSchemas(t *testing.T) {
+	svc := new(mockSummaryService)
+	app := setupApp(svc)
+
+	svc.On("GetSchemas", "sum-1", domain.ListOrder{Sort: domain.SortTableCount, Desc: true}, 2, 50).
+		Return(&domain.SchemaPage{Items: []domain.SchemaListItem{{ID: "sch-1", Name: "public", TableCount: 12}}, Total: 51}, nil)
+	svc.On("GetSchemas", "missing", domain.ListOrder{}, 1, 10).Return(nil, service.ErrSummaryNotFound)
+	svc.On("GetSchemas", "sum-1", domain.ListOrder{Sort: "rows"}, 1, 10).Return(nil, fmt.Errorf("%w: bad sort", service.ErrInvalidQuery))
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/summary/summaries/sum-1/schemas?page=2&pageSize=50&sort=table_count&order=desc", nil))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var page domain.SchemaPage
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&page))
+	assert.Equal(t, int64(51), page.Total)
+	assert.Equal(t, 12, page.Items[0].TableCount)
+
+	resp, _ = app.Test(httptest.NewRequest(http.MethodGet, "/summary/summaries/missing/schemas", nil))
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp, _ = app.Test(httptest.NewRequest(http.MethodGet, "/summary/summaries/sum-1/schemas?sort=rows", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestGetTables(t *testing.T) {
+	svc := new(mockSummaryService)
+	app := setupApp(svc)
+
+	svc.On("GetTables", "sum-1", "My Schema", domain.ListOrder{Sort: domain.SortSizeMB}, 1, 10).
+		Return(&domain.TablePage{Items: []domain.TableListItem{{ID: "t-1", Name: "orders"}}, Total: 1}, nil)
+	svc.On("GetTables", "sum-1", "nope", domain.ListOrder{}, 1, 10).Return(nil, service.ErrSchemaNotFound)
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/summary/summaries/sum-1/schemas/My%20Schema/tables?sort=size_mb", nil))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, _ = app.Test(httptest.NewRequest(http.MethodGet, "/summary/summaries/sum-1/schemas/nope/tables", nil))
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	svc.AssertExpectations(t)
+}
+
+func TestGetTable(t *testing.T) {
+	svc := new(mockSummaryService)
+	app := setupApp(svc)
+
+	svc.On("GetTable", "sum-1", "public", "orders").
+		Return(&domain.Table{ID: "t-1", Name: "orders", Columns: []domain.Column{{Name: "id"}}}, nil)
+	svc.On("GetTable", "sum-1", "public", "ghost").Return(nil, service.ErrTableNotFound)
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/summary/summaries/sum-1/schemas/public/tables/orders", nil))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var table domain.Table
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&table))
+	assert.Len(t, table.Columns, 1)
+
+	resp, _ = app.Test(httptest.NewRequest(http.MethodGet, "/summary/summaries/sum-1/schemas/public/tables/ghost", nil))
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
+	"github.com/lokesh2201013/postgres-data-summary/internal/service"
+)
+
+type TrendHandler interface {
+	GetSourceTrends(c *fiber.Ctx) error
+}
+
+type trendHandlerImpl struct {
+	service service.ITrendService
+}
+
+func NewTrendHandler(service service.ITrendService) TrendHandler {
+	return &trendHandlerImpl{service: service}
+}
+
+// GetSourceTrends godoc
+// @Summary Get growth trends of a source database
+// @Description Computes row count and size growth of a source database and each table of its newest snapshot over each window, from its snapshot history. Rates are least-squares slopes per day. With thresholdMb, forecasts when the database reaches that size at the growth rate of the longest window.
+// @Tags trends
+// @Produce  json
+// @Param id path string true "Source ID"
+// @Param windows query string false "Comma-separated growth windows in days (default 7,30,90)"
+// @Param thresholdMb query number false "Database size to forecast against"
+// @Success 200 {object} domain.SourceTrends
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /summary/sources/{id}/trends [get]
+func (h *trendHandlerImpl) GetSourceTrends(c *fiber.Ctx) error {
+	sourceID := c.Params("id")
+	var query domain.TrendQuery
+	if raw := c.Query("windows"); raw != "" {
+		for _, field := range strings.Split(raw, ",") {
+			days, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "windows must be a comma-separated list of days")
+			}
+			query.Windows = append(query.Windows, days)
+		}
+	}
+	if raw := c.Query("thresholdMb"); raw != "" {
+		threshold, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "thresholdMb must be a number")
+		}
+		query.ThresholdMB = &threshold
+	}
+
+	trends, err := h.service.GetSourceTrends(sourceID, query)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidQuery):
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrSourceNotFound):
+			return fiber.NewError(fiber.StatusNotFound, "No snapshots found for source")
+		}
+		logger.Log.Error("Failed to get source trends", zap.String("sourceID", sourceID), zap.Error(err))
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get trends")
+	}
+	return c.Status(fiber.StatusOK).JSON(trends)
+}
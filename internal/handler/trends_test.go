@@ -0,0 +1,74 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/handler"
+	"github.com/lokesh2201013/postgres-data-summary/internal/router"
+	"github.com/lokesh2201013/postgres-data-summary/internal/service"
+)
+
+type mockTrendService struct {
+	mock.Mock
+}
+
+func (m *mockTrendService) GetSourceTrends(sourceID string, query domain.TrendQuery) (*domain.SourceTrends, error) {
+	args := m.Called(sourceID, query)
+	if t := args.Get(0); t != nil {
+		return t.(*domain.SourceTrends), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func setupTrendApp(svc *mockTrendService) *fiber.App {
+	app := fiber.New()
+	router.TrendRoutes(app, handler.NewTrendHandler(svc))
+	return app
+}
+
+func TestGetSourceTrends(t *testing.T) {
+	svc := new(mockTrendService)
+	app := setupTrendApp(svc)
+
+	threshold := 2048.0
+	svc.On("GetSourceTrends", "src-1", domain.TrendQuery{Windows: []int{7, 30}, ThresholdMB: &threshold}).
+		Return(&domain.SourceTrends{SourceID: "src-1", DBName: "billing",
+			Forecast: &domain.CapacityForecast{ThresholdMB: threshold, WindowDays: 30}}, nil)
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/summary/sources/src-1/trends?windows=7,%2030&thresholdMb=2048", nil))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var trends domain.SourceTrends
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&trends))
+	assert.Equal(t, "billing", trends.DBName)
+	assert.Equal(t, 30, trends.Forecast.WindowDays)
+}
+
+func TestGetSourceTrends_Errors(t *testing.T) {
+	svc := new(mockTrendService)
+	app := setupTrendApp(svc)
+
+	svc.On("GetSourceTrends", "missing", domain.TrendQuery{}).Return(nil, service.ErrSourceNotFound)
+	svc.On("GetSourceTrends", "src-1", domain.TrendQuery{Windows: []int{0}}).Return(nil, fmt.Errorf("%w: bad window", service.ErrInvalidQuery))
+	svc.On("GetSourceTrends", "src-1", domain.TrendQuery{}).Return(nil, errors.New("db down"))
+
+	for url, status := range map[string]int{
+		"/summary/sources/missing/trends":             http.StatusNotFound,
+		"/summary/sources/src-1/trends?windows=0":     http.StatusBadRequest,
+		"/summary/sources/src-1/trends?windows=week":  http.StatusBadRequest,
+		"/summary/sources/src-1/trends?thresholdMb=":  http.StatusInternalServerError,
+		"/summary/sources/src-1/trends?thresholdMb=x": http.StatusBadRequest,
+	} {
+		resp, _ := app.Test(httptest.NewRequest(http.MethodGet, url, nil))
+		assert.Equal(t, status, resp.StatusCode, url)
+	}
+}
@@ -0,0 +1,289 @@
+package introspect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Querier is the subset of *pgx.Conn, pgx.Tx and *pgxpool.Pool the catalog
+// queries need.
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// query runs sql and scans every row into T, matching columns to fields by
+// their snake_case names.
+func query[T any](ctx context.Context, db Querier, sql string) ([]T, error) {
+	rows, err := db.Query(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[T])
+}
+
+// systemSchemaFilter excludes catalog, information_schema, toast and temp
+// namespaces so only user-visible schemas are summarised.
+const systemSchemaFilter = `n.nspname NOT IN ('pg_catalog', 'information_schema')
+	AND n.nspname NOT LIKE 'pg_toast%'
+	AND n.nspname NOT LIKE 'pg_temp_%'`
+
+type SchemaRow struct {
+	Name string
+}
+
+type TableRow struct {
+	SchemaName string
+	Name       string
+	RowCount   int64
+	SizeMB     float64
+}
+
+// ListSchemas returns every user schema in the connected database, including
+// schemas without any tables.
+func ListSchemas(ctx context.Context, db Querier) ([]SchemaRow, error) {
+	return query[SchemaRow](ctx, db, `
+		SELECT n.nspname AS name
+		FROM pg_namespace n
+		WHERE `+systemSchemaFilter+`
+		ORDER BY n.nspname`)
+}
+
+// ListTables returns ordinary and partitioned tables with their estimated row
+// count (pg_class.reltuples) and total on-disk size including indexes and toast.
+func ListTables(ctx context.Context, db Querier) ([]TableRow, error) {
+	return query[TableRow](ctx, db, `
+		SELECT n.nspname AS schema_name,
+		       c.relname AS name,
+		       GREATEST(c.reltuples, 0)::bigint AS row_count,
+		       pg_total_relation_size(c.oid) / 1024.0 / 1024.0 AS size_mb
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind IN ('r', 'p')
+		  AND `+systemSchemaFilter+`
+		ORDER BY n.nspname, c.relname`)
+}
+
+type ColumnRow struct {
+	SchemaName      string
+	TableName       string
+	Name            string
+	OrdinalPosition int
+	DataType        string
+	IsNullable      bool
+	DefaultValue    *string
+	Collation       *string
+	IsIdentity      bool
+	IsGenerated     bool
+}
+
+// ListColumns returns the live (non-dropped) columns of every table returned
+// by ListTables. Defaults hold the generation expression for generated columns.
+func ListColumns(ctx context.Context, db Querier) ([]ColumnRow, error) {
+	return query[ColumnRow](ctx, db, `
+		SELECT n.nspname AS schema_name,
+		       c.relname AS table_name,
+		       a.attname AS name,
+		       a.attnum AS ordinal_position,
+		       format_type(a.atttypid, a.atttypmod) AS data_type,
+		       NOT a.attnotnull AS is_nullable,
+		       pg_get_expr(d.adbin, d.adrelid) AS default_value,
+		       co.collname AS collation,
+		       a.attidentity <> '' AS is_identity,
+		       a.attgenerated <> '' AS is_generated
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		LEFT JOIN pg_collation co ON co.oid = a.attcollation AND a.attcollation <> 0
+		WHERE a.attnum > 0
+		  AND NOT a.attisdropped
+		  AND c.relkind IN ('r', 'p')
+		  AND `+systemSchemaFilter+`
+		ORDER BY n.nspname, c.relname, a.attnum`)
+}
+
+type IndexRow struct {
+	SchemaName    string
+	TableName     string
+	Name          string
+	ColumnsJSON   string
+	IsUnique      bool
+	IsPrimary     bool
+	Predicate     *string
+	Method        string
+	Definition    string
+	SizeMB        float64
+	Scans         int64
+	TuplesRead    int64
+	TuplesFetched int64
+}
+
+// Columns decodes the key columns/expressions of the index in key order.
+func (r IndexRow) Columns() []string {
+	return decodeNames(r.ColumnsJSON)
+}
+
+// decodeNames decodes a JSON text array produced by to_json(ARRAY(...)),
+// returning nil for an empty or malformed array.
+func decodeNames(raw string) []string {
+	var names []string
+	if err := json.Unmarshal([]byte(raw), &names); err != nil || len(names) == 0 {
+		return nil
+	}
+	return names
+}
+
+// ListIndexes returns every index on the tables returned by ListTables along
+// with its access method, size and pg_stat_user_indexes usage counters.
+func ListIndexes(ctx context.Context, db Querier) ([]IndexRow, error) {
+	return query[IndexRow](ctx, db, `
+		SELECT n.nspname AS schema_name,
+		       t.relname AS table_name,
+		       ic.relname AS name,
+		       to_json(ARRAY(
+		           SELECT pg_get_indexdef(i.indexrelid, k, true)
+		           FROM generate_series(1, i.indnkeyatts) AS k
+		           ORDER BY k
+		       ))::text AS columns_json,
+		       i.indisunique AS is_unique,
+		       i.indisprimary AS is_primary,
+		       pg_get_expr(i.indpred, i.indrelid, true) AS predicate,
+		       am.amname AS method,
+		       pg_get_indexdef(i.indexrelid) AS definition,
+		       pg_relation_size(i.indexrelid) / 1024.0 / 1024.0 AS size_mb,
+		       COALESCE(s.idx_scan, 0) AS scans,
+		       COALESCE(s.idx_tup_read, 0) AS tuples_read,
+		       COALESCE(s.idx_tup_fetch, 0) AS tuples_fetched
+		FROM pg_index i
+		JOIN pg_class ic ON ic.oid = i.indexrelid
+		JOIN pg_class t ON t.oid = i.indrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN pg_am am ON am.oid = ic.relam
+		LEFT JOIN pg_stat_user_indexes s ON s.indexrelid = i.indexrelid
+		WHERE t.relkind IN ('r', 'p')
+		  AND `+systemSchemaFilter+`
+		ORDER BY n.nspname, t.relname, ic.relname`)
+}
+
+type ConstraintRow struct {
+	SchemaName            string
+	TableName             string
+	Name                  string
+	Type                  string
+	ColumnsJSON           string
+	Definition            string
+	ReferencedSchema      *string
+	ReferencedTable       *string
+	ReferencedColumnsJSON string
+	OnDelete              *string
+	OnUpdate              *string
+}
+
+func (r ConstraintRow) Columns() []string {
+	return decodeNames(r.ColumnsJSON)
+}
+
+func (r ConstraintRow) ReferencedColumns() []string {
+	return decodeNames(r.ReferencedColumnsJSON)
+}
+
+// constraintColumns renders the attribute names of a pg_constraint key array
+// (conkey/confkey) of the given relation as a JSON array, in key order.
+const constraintColumns = `to_json(ARRAY(
+		           SELECT a.attname
+		           FROM unnest(%[1]s) WITH ORDINALITY AS k(attnum, ord)
+		           JOIN pg_attribute a ON a.attrelid = %[2]s AND a.attnum = k.attnum
+		           ORDER BY k.ord
+		       ))::text`
+
+// fkAction maps a pg_constraint confdeltype/confupdtype code to its SQL name.
+const fkAction = `CASE %[1]s
+		           WHEN 'a' THEN 'NO ACTION'
+		           WHEN 'r' THEN 'RESTRICT'
+		           WHEN 'c' THEN 'CASCADE'
+		           WHEN 'n' THEN 'SET NULL'
+		           WHEN 'd' THEN 'SET DEFAULT'
+		       END`
+
+// ListConstraints returns primary key, unique, check and foreign key
+// constraints of every table returned by ListTables. Referenced table,
+// columns and actions are only populated for foreign keys.
+func ListConstraints(ctx context.Context, db Querier) ([]ConstraintRow, error) {
+	return query[ConstraintRow](ctx, db, `
+		SELECT n.nspname AS schema_name,
+		       t.relname AS table_name,
+		       con.conname AS name,
+		       CASE con.contype
+		           WHEN 'p' THEN 'primary_key'
+		           WHEN 'u' THEN 'unique'
+		           WHEN 'c' THEN 'check'
+		           WHEN 'f' THEN 'foreign_key'
+		       END AS type,
+		       `+fmt.Sprintf(constraintColumns, "con.conkey", "con.conrelid")+` AS columns_json,
+		       pg_get_constraintdef(con.oid, true) AS definition,
+		       rn.nspname AS referenced_schema,
+		       rt.relname AS referenced_table,
+		       `+fmt.Sprintf(constraintColumns, "con.confkey", "con.confrelid")+` AS referenced_columns_json,
+		       CASE WHEN con.contype = 'f' THEN `+fmt.Sprintf(fkAction, "con.confdeltype")+` END AS on_delete,
+		       CASE WHEN con.contype = 'f' THEN `+fmt.Sprintf(fkAction, "con.confupdtype")+` END AS on_update
+		FROM pg_constraint con
+		JOIN pg_class t ON t.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		LEFT JOIN pg_class rt ON rt.oid = con.confrelid
+		LEFT JOIN pg_namespace rn ON rn.oid = rt.relnamespace
+		WHERE con.contype IN ('p', 'u', 'c', 'f')
+		  AND t.relkind IN ('r', 'p')
+		  AND `+systemSchemaFilter+`
+		ORDER BY n.nspname, t.relname, con.conname`)
+}
+
+type DatabaseRow struct {
+	Name string
+}
+
+// ListDatabases returns the databases of the server db is connected to,
+// skipping templates and databases that do not accept connections.
+func ListDatabases(ctx context.Context, db Querier) ([]DatabaseRow, error) {
+	return query[DatabaseRow](ctx, db, `
+		SELECT datname AS name
+		FROM pg_database
+		WHERE NOT datistemplate AND datallowconn
+		ORDER BY datname`)
+}
+
+// Catalog is the raw, flat result of reading a database's system catalogs.
+type Catalog struct {
+	Schemas     []SchemaRow
+	Tables      []TableRow
+	Columns     []ColumnRow
+	Indexes     []IndexRow
+	Constraints []ConstraintRow
+}
+
+// ReadCatalog runs every catalog query against db. Pass a REPEATABLE READ
+// transaction for the results to be consistent with each other.
+func ReadCatalog(ctx context.Context, db Querier) (*Catalog, error) {
+	var (
+		catalog Catalog
+		err     error
+	)
+	if catalog.Schemas, err = ListSchemas(ctx, db); err != nil {
+		return nil, fmt.Errorf("list schemas: %w", err)
+	}
+	if catalog.Tables, err = ListTables(ctx, db); err != nil {
+		return nil, fmt.Errorf("list tables: %w", err)
+	}
+	if catalog.Columns, err = ListColumns(ctx, db); err != nil {
+		return nil, fmt.Errorf("list columns: %w", err)
+	}
+	if catalog.Indexes, err = ListIndexes(ctx, db); err != nil {
+		return nil, fmt.Errorf("list indexes: %w", err)
+	}
+	if catalog.Constraints, err = ListConstraints(ctx, db); err != nil {
+		return nil, fmt.Errorf("list constraints: %w", err)
+	}
+	return &catalog, nil
+}
@@ -0,0 +1,60 @@
+package introspect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+)
+
+// ErrConnect wraps failures to reach or authenticate with the target
+// database, as opposed to failures while reading its catalog.
+var ErrConnect = errors.New("connect to target database")
+
+// ErrRejected wraps connection attempts the server refused outright: bad
+// credentials, a pg_hba.conf rule or a database that does not exist.
+// Retrying them cannot succeed.
+var ErrRejected = errors.New("target database rejected the connection")
+
+// Connect opens a single connection to the database described by details.
+// Callers must close it once they are done.
+func Connect(ctx context.Context, details domain.ConnectionDetails) (*pgx.Conn, error) {
+	conn, err := pgx.Connect(ctx, connString(details))
+	if err != nil {
+		if rejected(err) {
+			return nil, fmt.Errorf("%w: %w: %w", ErrConnect, ErrRejected, err)
+		}
+		return nil, fmt.Errorf("%w: %w", ErrConnect, err)
+	}
+	return conn, nil
+}
+
+// connString returns the libpq keyword/value connection string for details.
+func connString(details domain.ConnectionDetails) string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		dsnValue(details.Host), details.PortOrDefault(), dsnValue(details.User), dsnValue(details.Password),
+		dsnValue(details.DBName), dsnValue(details.SSLModeOrDefault()))
+}
+
+// rejected reports whether err carries an invalid authorization (class 28)
+// or invalid catalog name (3D000) error from the server.
+func rejected(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return strings.HasPrefix(pgErr.Code, "28") || pgErr.Code == "3D000"
+}
+
+// dsnValue quotes a libpq keyword/value so passwords or names containing
+// spaces and quotes survive DSN parsing.
+func dsnValue(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `'`, `\'`)
+	return "'" + v + "'"
+}
@@ -0,0 +1,164 @@
+// Package introspect reads the system catalogs of a PostgreSQL database and
+// builds a domain.Summary from them. It backs both external-service and the
+// in-process summary client, so the two produce identical summaries.
+package introspect
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+)
+
+// Summarize connects to the database described by details, reads its
+// catalog in one read-only REPEATABLE READ transaction and returns the
+// summary. The password is not copied into the
+// result.
+func Summarize(ctx context.Context, details domain.ConnectionDetails) (domain.Summary, error) {
+	conn, err := Connect(ctx, details)
+	if err != nil {
+		return domain.Summary{}, err
+	}
+	defer conn.Close(context.Background())
+
+	// Every catalog query reads the same snapshot, so DDL running during the
+	// sync cannot leave columns or indexes pointing at tables not listed.
+	tx, err := conn.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return domain.Summary{}, fmt.Errorf("begin catalog snapshot: %w", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	catalog, err := ReadCatalog(ctx, tx)
+	if err != nil {
+		return domain.Summary{}, fmt.Errorf("read catalog: %w", err)
+	}
+
+	now := time.Now()
+	details.Password = ""
+	return domain.Summary{
+		ID:         uuid.NewString(),
+		Name:       details.DBName,
+		SyncedAt:   now,
+		SourceInfo: details,
+		Schemas:    BuildSchemas(catalog, now),
+	}, nil
+}
+
+// MaintenanceDB is connected to when listing the databases of a server
+// without naming one.
+const MaintenanceDB = "postgres"
+
+// Databases connects to the server described by details, using its DBName
+// or MaintenanceDB, and lists the databases on it.
+func Databases(ctx context.Context, details domain.ConnectionDetails) ([]string, error) {
+	if details.DBName == "" {
+		details.DBName = MaintenanceDB
+	}
+	conn, err := Connect(ctx, details)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close(context.Background())
+
+	rows, err := ListDatabases(ctx, conn)
+	if err != nil {
+		return nil, fmt.Errorf("list databases: %w", err)
+	}
+	names := make([]string, len(rows))
+	for i, row := range rows {
+		names[i] = row.Name
+	}
+	return names, nil
+}
+
+// BuildSchemas nests the flat catalog rows into the Schema/Table tree,
+// preserving the catalog ordering.
+func BuildSchemas(catalog *Catalog, syncedAt time.Time) []domain.Schema {
+	result := make([]domain.Schema, 0, len(catalog.Schemas))
+	schemaIndex := make(map[string]int, len(catalog.Schemas))
+	for _, s := range catalog.Schemas {
+		schemaIndex[s.Name] = len(result)
+		result = append(result, domain.Schema{Name: s.Name, SyncedAt: syncedAt, Tables: []domain.Table{}})
+	}
+
+	type tablePos struct{ schema, table int }
+	tableIndex := make(map[[2]string]tablePos, len(catalog.Tables))
+	for _, t := range catalog.Tables {
+		i, ok := schemaIndex[t.SchemaName]
+		if !ok {
+			continue
+		}
+		tableIndex[[2]string{t.SchemaName, t.Name}] = tablePos{i, len(result[i].Tables)}
+		result[i].Tables = append(result[i].Tables, domain.Table{
+			Name:        t.Name,
+			RowCount:    t.RowCount,
+			SizeMB:      t.SizeMB,
+			Columns:     []domain.Column{},
+			Indexes:     []domain.Index{},
+			Constraints: []domain.Constraint{},
+		})
+	}
+
+	tableFor := func(schema, table string) *domain.Table {
+		pos, ok := tableIndex[[2]string{schema, table}]
+		if !ok {
+			return nil
+		}
+		return &result[pos.schema].Tables[pos.table]
+	}
+
+	for _, col := range catalog.Columns {
+		if table := tableFor(col.SchemaName, col.TableName); table != nil {
+			table.Columns = append(table.Columns, domain.Column{
+				Name:            col.Name,
+				OrdinalPosition: col.OrdinalPosition,
+				DataType:        col.DataType,
+				IsNullable:      col.IsNullable,
+				DefaultValue:    col.DefaultValue,
+				Collation:       col.Collation,
+				IsIdentity:      col.IsIdentity,
+				IsGenerated:     col.IsGenerated,
+			})
+		}
+	}
+
+	for _, idx := range catalog.Indexes {
+		if table := tableFor(idx.SchemaName, idx.TableName); table != nil {
+			table.Indexes = append(table.Indexes, domain.Index{
+				Name:          idx.Name,
+				Columns:       idx.Columns(),
+				IsUnique:      idx.IsUnique,
+				IsPrimary:     idx.IsPrimary,
+				Predicate:     idx.Predicate,
+				Method:        idx.Method,
+				Definition:    idx.Definition,
+				SizeMB:        idx.SizeMB,
+				Scans:         idx.Scans,
+				TuplesRead:    idx.TuplesRead,
+				TuplesFetched: idx.TuplesFetched,
+			})
+		}
+	}
+
+	for _, con := range catalog.Constraints {
+		if table := tableFor(con.SchemaName, con.TableName); table != nil {
+			table.Constraints = append(table.Constraints, domain.Constraint{
+				Name:              con.Name,
+				Type:              con.Type,
+				Columns:           con.Columns(),
+				Definition:        con.Definition,
+				ReferencedSchema:  con.ReferencedSchema,
+				ReferencedTable:   con.ReferencedTable,
+				ReferencedColumns: con.ReferencedColumns(),
+				OnDelete:          con.OnDelete,
+				OnUpdate:          con.OnUpdate,
+			})
+		}
+	}
+	return result
+}
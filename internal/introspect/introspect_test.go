@@ -0,0 +1,85 @@
+package introspect
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestBuildSchemas(t *testing.T) {
+	syncedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	catalog := &Catalog{
+		Schemas: []SchemaRow{{Name: "empty"}, {Name: "public"}},
+		Tables: []TableRow{
+			{SchemaName: "public", Name: "customers", RowCount: 10, SizeMB: 1.5},
+			{SchemaName: "public", Name: "orders", RowCount: 20, SizeMB: 2},
+			{SchemaName: "missing", Name: "ghost"},
+		},
+		Columns: []ColumnRow{
+			{SchemaName: "public", TableName: "orders", Name: "id", OrdinalPosition: 1, DataType: "bigint"},
+			{SchemaName: "public", TableName: "orders", Name: "customer_id", OrdinalPosition: 2, DataType: "bigint", IsNullable: true},
+		},
+		Indexes: []IndexRow{
+			{SchemaName: "public", TableName: "orders", Name: "orders_pkey", ColumnsJSON: `["id"]`, IsUnique: true, IsPrimary: true, Method: "btree"},
+		},
+		Constraints: []ConstraintRow{
+			{
+				SchemaName: "public", TableName: "orders", Name: "orders_customer_fk", Type: "foreign_key",
+				ColumnsJSON: `["customer_id"]`, ReferencedSchema: strPtr("public"), ReferencedTable: strPtr("customers"),
+				ReferencedColumnsJSON: `["id"]`, OnDelete: strPtr("CASCADE"), OnUpdate: strPtr("NO ACTION"),
+			},
+		},
+	}
+
+	schemas := BuildSchemas(catalog, syncedAt)
+
+	assert.Len(t, schemas, 2)
+	assert.Equal(t, "empty", schemas[0].Name)
+	assert.Empty(t, schemas[0].Tables)
+	assert.Equal(t, syncedAt, schemas[1].SyncedAt)
+
+	tables := schemas[1].Tables
+	assert.Len(t, tables, 2, "tables of unknown schemas are dropped")
+	assert.Empty(t, tables[0].Columns)
+
+	orders := tables[1]
+	assert.Equal(t, "orders", orders.Name)
+	assert.Equal(t, int64(20), orders.RowCount)
+	assert.Equal(t, []string{"id", "customer_id"}, []string{orders.Columns[0].Name, orders.Columns[1].Name})
+	assert.Equal(t, []string{"id"}, []string(orders.Indexes[0].Columns))
+	assert.Equal(t, "customers", *orders.Constraints[0].ReferencedTable)
+	assert.Equal(t, []string{"id"}, []string(orders.Constraints[0].ReferencedColumns))
+}
+
+func TestDecodeNames(t *testing.T) {
+	assert.Equal(t, []string{"a", "lower(b)"}, decodeNames(`["a","lower(b)"]`))
+	assert.Nil(t, decodeNames(`[]`))
+	assert.Nil(t, decodeNames(``))
+}
+
+func TestConnString(t *testing.T) {
+	details := domain.ConnectionDetails{Host: "db", User: "app", Password: "it's", DBName: "sales"}
+	assert.Equal(t, `host='db' port=5432 user='app' password='it\'s' dbname='sales' sslmode='prefer'`, connString(details))
+
+	details.SSLMode = "verify-full"
+	assert.Contains(t, connString(details), "sslmode='verify-full'")
+}
+
+func TestRejected(t *testing.T) {
+	wrap := func(code string) error {
+		return fmt.Errorf("failed to connect: %w", &pgconn.PgError{Code: code})
+	}
+	assert.True(t, rejected(wrap("28P01")))
+	assert.True(t, rejected(wrap("28000")))
+	assert.True(t, rejected(wrap("3D000")))
+	assert.False(t, rejected(wrap("57P03")))
+	assert.False(t, rejected(errors.New("dial tcp: connection refused")))
+}
@@ -0,0 +1,86 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/lokesh2201013/postgres-data-summary/external-service/routes"
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/introspect"
+	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
+)
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// normalize clears the fields that legitimately differ between two syncs of
+// the same database.
+func normalize(t *testing.T, summary domain.Summary) string {
+	summary.ID = ""
+	summary.SyncedAt = time.Time{}
+	for i := range summary.Schemas {
+		summary.Schemas[i].SyncedAt = time.Time{}
+	}
+	out, err := json.Marshal(summary)
+	assert.NoError(t, err)
+	return string(out)
+}
+
+func TestBackendsProduceIdenticalSummaries(t *testing.T) {
+	port, _ := strconv.Atoi(getEnv("DB_PORT", "5432"))
+	details := domain.ConnectionDetails{
+		Host:     getEnv("DB_HOST", "localhost"),
+		Port:     &port,
+		User:     getEnv("DB_USER", "postgres"),
+		Password: getEnv("DB_PASSWORD", "postgres"),
+		DBName:   getEnv("DB_NAME", "postgres_test"),
+	}
+	conn, err := introspect.Connect(context.Background(), details)
+	if err != nil {
+		t.Skipf("test postgres not reachable: %v", err)
+	}
+	conn.Close(context.Background())
+
+	logger.Log = zap.NewNop()
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	routes.SetupRoutes(app, "")
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go app.Listener(ln)
+	defer app.Shutdown()
+
+	httpClient, err := NewSummaryClient(ClientConfig{BaseURL: "http://" + ln.Addr().String()})
+	assert.NoError(t, err)
+	directClient, err := NewSummaryClient(ClientConfig{Backend: BackendDirect})
+	assert.NoError(t, err)
+
+	viaHTTP, err := httpClient.FetchSummary(context.Background(), details)
+	assert.NoError(t, err)
+	direct, err := directClient.FetchSummary(context.Background(), details)
+	assert.NoError(t, err)
+
+	assert.NotEmpty(t, direct.Schemas)
+	assert.Empty(t, direct.SourceInfo.Password)
+	assert.JSONEq(t, normalize(t, viaHTTP), normalize(t, direct))
+
+	httpDatabases, err := httpClient.ListDatabases(context.Background(), details)
+	assert.NoError(t, err)
+	directDatabases, err := directClient.ListDatabases(context.Background(), details)
+	assert.NoError(t, err)
+	assert.Contains(t, directDatabases, details.DBName)
+	assert.NotContains(t, directDatabases, "template0")
+	assert.Equal(t, directDatabases, httpDatabases)
+}
@@ -0,0 +1,190 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/config"
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+)
+
+// ErrCircuitOpen is returned without contacting the target while its circuit
+// is open.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// BreakerConfig tunes NewBreaker.
+type BreakerConfig struct {
+	// Threshold is the number of consecutive transient failures that opens
+	// the circuit of a target.
+	Threshold int
+	// OpenTimeout is how long a circuit stays open before a single probe
+	// fetch is let through.
+	OpenTimeout time.Duration
+}
+
+// CircuitMonitor exposes the breaker state for the admin endpoints.
+type CircuitMonitor interface {
+	Circuits() []domain.CircuitState
+	// ResetCircuit closes the circuit of target, reporting whether the
+	// breaker knew about it.
+	ResetCircuit(target string) bool
+}
+
+// Breaker is a SummaryClient that keeps one circuit per target host:port in
+// front of another client. Only failures Retryable accepts count against a
+// target; a refused login shows the server is up and closes its circuit.
+type Breaker struct {
+	client      SummaryClient
+	threshold   int
+	openTimeout time.Duration
+	now         func() time.Time
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	state     string
+	failures  int
+	lastError string
+	openedAt  time.Time
+	// probing is set while the single half-open probe is in flight.
+	probing bool
+}
+
+func NewBreaker(client SummaryClient, cfg BreakerConfig) *Breaker {
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = config.DefaultBreakerThreshold
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = config.DefaultBreakerOpenTimeout
+	}
+	return &Breaker{
+		client:      client,
+		threshold:   cfg.Threshold,
+		openTimeout: cfg.OpenTimeout,
+		now:         time.Now,
+		circuits:    make(map[string]*circuit),
+	}
+}
+
+// FetchSummary fetches through the wrapped client unless the circuit of the
+// target is open, in which case it fails fast with ErrCircuitOpen.
+func (b *Breaker) FetchSummary(ctx context.Context, details domain.ConnectionDetails) (domain.Summary, error) {
+	target := circuitTarget(details)
+	if err := b.allow(target); err != nil {
+		return domain.Summary{}, err
+	}
+	summary, err := b.client.FetchSummary(ctx, details)
+	b.record(target, err)
+	return summary, err
+}
+
+// ListDatabases lists through the wrapped client under the same circuit as
+// FetchSummary.
+func (b *Breaker) ListDatabases(ctx context.Context, details domain.ConnectionDetails) ([]string, error) {
+	target := circuitTarget(details)
+	if err := b.allow(target); err != nil {
+		return nil, err
+	}
+	databases, err := b.client.ListDatabases(ctx, details)
+	b.record(target, err)
+	return databases, err
+}
+
+func (b *Breaker) allow(target string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuits[target]
+	if c == nil {
+		c = &circuit{state: domain.CircuitClosed}
+		b.circuits[target] = c
+	}
+	if c.state == domain.CircuitOpen && !b.now().Before(c.openedAt.Add(b.openTimeout)) {
+		c.state = domain.CircuitHalfOpen
+	}
+
+	switch {
+	case c.state == domain.CircuitClosed:
+		return nil
+	case c.state == domain.CircuitHalfOpen && !c.probing:
+		c.probing = true
+		return nil
+	}
+	return fmt.Errorf("%w for %s until %s: %s",
+		ErrCircuitOpen, target, c.openedAt.Add(b.openTimeout).Format(time.RFC3339), c.lastError)
+}
+
+func (b *Breaker) record(target string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuits[target]
+	c.probing = false
+	switch {
+	case errors.Is(err, context.Canceled):
+		// The caller gave up; this says nothing about the target.
+	case err == nil || !Retryable(err):
+		c.state = domain.CircuitClosed
+		c.failures = 0
+		c.lastError = ""
+	default:
+		c.failures++
+		c.lastError = err.Error()
+		if c.state == domain.CircuitHalfOpen || c.failures >= b.threshold {
+			c.state = domain.CircuitOpen
+			c.openedAt = b.now()
+		}
+	}
+}
+
+// Circuits returns the state of every target fetched so far, ordered by
+// target.
+func (b *Breaker) Circuits() []domain.CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	states := make([]domain.CircuitState, 0, len(b.circuits))
+	for target, c := range b.circuits {
+		state := domain.CircuitState{
+			Target:              target,
+			State:               c.state,
+			ConsecutiveFailures: c.failures,
+			LastError:           c.lastError,
+		}
+		if c.state != domain.CircuitClosed {
+			openedAt, retryAt := c.openedAt, c.openedAt.Add(b.openTimeout)
+			state.OpenedAt, state.RetryAt = &openedAt, &retryAt
+			if c.state == domain.CircuitOpen && !b.now().Before(retryAt) {
+				state.State = domain.CircuitHalfOpen
+			}
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Target < states[j].Target })
+	return states
+}
+
+func (b *Breaker) ResetCircuit(target string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[target]
+	if ok {
+		*c = circuit{state: domain.CircuitClosed}
+	}
+	return ok
+}
+
+// circuitTarget keys circuits by host and port so databases sharing a
+// server share a circuit.
+func circuitTarget(details domain.ConnectionDetails) string {
+	return net.JoinHostPort(details.Host, strconv.Itoa(details.PortOrDefault()))
+}
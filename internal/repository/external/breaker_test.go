@@ -0,0 +1,113 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/introspect"
+)
+
+// stubClient returns the queued errors in order, then succeeds.
+type stubClient struct {
+	errs  []error
+	calls int
+}
+
+func (c *stubClient) FetchSummary(ctx context.Context, details domain.ConnectionDetails) (domain.Summary, error) {
+	c.calls++
+	if len(c.errs) == 0 {
+		return domain.Summary{ID: "sum"}, nil
+	}
+	err := c.errs[0]
+	c.errs = c.errs[1:]
+	return domain.Summary{}, err
+}
+
+func (c *stubClient) ListDatabases(ctx context.Context, details domain.ConnectionDetails) ([]string, error) {
+	_, err := c.FetchSummary(ctx, details)
+	return []string{"demo"}, err
+}
+
+func newTestBreaker(client SummaryClient) (*Breaker, *time.Time) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker := NewBreaker(client, BreakerConfig{Threshold: 2, OpenTimeout: time.Minute})
+	breaker.now = func() time.Time { return now }
+	return breaker, &now
+}
+
+func TestBreaker_OpensAndHalfOpens(t *testing.T) {
+	down := errors.New("connection refused")
+	client := &stubClient{errs: []error{down, down, down, down}}
+	breaker, now := newTestBreaker(client)
+	db := domain.ConnectionDetails{Host: "db"}
+	ctx := context.Background()
+
+	breaker.FetchSummary(ctx, db)
+	breaker.FetchSummary(ctx, db)
+	_, err := breaker.FetchSummary(ctx, db)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.False(t, Retryable(err))
+	assert.Equal(t, 2, client.calls)
+
+	// Other targets are unaffected.
+	_, err = breaker.FetchSummary(ctx, domain.ConnectionDetails{Host: "db", Port: intPtr(6432)})
+	assert.ErrorIs(t, err, down)
+
+	// After the timeout one probe goes through; it fails and reopens.
+	*now = now.Add(time.Minute)
+	assert.Equal(t, domain.CircuitHalfOpen, breaker.Circuits()[0].State)
+	_, err = breaker.FetchSummary(ctx, db)
+	assert.ErrorIs(t, err, down)
+	_, err = breaker.FetchSummary(ctx, db)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	// The next probe succeeds and closes the circuit.
+	*now = now.Add(time.Minute)
+	summary, err := breaker.FetchSummary(ctx, db)
+	assert.NoError(t, err)
+	assert.Equal(t, "sum", summary.ID)
+
+	states := breaker.Circuits()
+	assert.Equal(t, "db:5432", states[0].Target)
+	assert.Equal(t, domain.CircuitClosed, states[0].State)
+	assert.Zero(t, states[0].ConsecutiveFailures)
+	assert.Nil(t, states[0].RetryAt)
+}
+
+func TestBreaker_IgnoresPermanentErrors(t *testing.T) {
+	rejected := fmt.Errorf("%w: %w", introspect.ErrConnect, introspect.ErrRejected)
+	client := &stubClient{errs: []error{rejected, rejected, rejected, context.Canceled}}
+	breaker, _ := newTestBreaker(client)
+
+	for i := 0; i < 4; i++ {
+		breaker.FetchSummary(context.Background(), domain.ConnectionDetails{Host: "db"})
+	}
+	assert.Equal(t, 4, client.calls)
+	assert.Equal(t, domain.CircuitClosed, breaker.Circuits()[0].State)
+}
+
+func TestBreaker_Reset(t *testing.T) {
+	down := errors.New("connection refused")
+	breaker, _ := newTestBreaker(&stubClient{errs: []error{down, down}})
+	db := domain.ConnectionDetails{Host: "db"}
+
+	breaker.FetchSummary(context.Background(), db)
+	breaker.FetchSummary(context.Background(), db)
+	states := breaker.Circuits()
+	assert.Equal(t, domain.CircuitOpen, states[0].State)
+	assert.Equal(t, "connection refused", states[0].LastError)
+	assert.NotNil(t, states[0].RetryAt)
+
+	assert.True(t, breaker.ResetCircuit("db:5432"))
+	assert.False(t, breaker.ResetCircuit("other:5432"))
+	_, err := breaker.FetchSummary(context.Background(), db)
+	assert.NoError(t, err)
+}
+
+func intPtr(i int) *int { return &i }
@@ -0,0 +1,69 @@
+package external
+
+import (
+	"time"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/config"
+)
+
+// Summary backends selectable with config.ExternalConfig.Backend.
+const (
+	// BackendHTTP calls external-service over HTTP.
+	BackendHTTP = "http"
+	// BackendDirect reads the target catalog from this process.
+	BackendDirect = "direct"
+)
+
+// ClientConfig selects and configures the SummaryClient. Only Timeout
+// applies to the direct backend.
+type ClientConfig struct {
+	// Backend is BackendHTTP (the default) or BackendDirect.
+	Backend string
+	// BaseURL is the scheme, host and optional path prefix of the service.
+	BaseURL string
+	// Timeout bounds each request; zero means
+	// config.DefaultExternalTimeout.
+	Timeout time.Duration
+	// Headers are sent with every request, e.g. an Authorization header.
+	Headers map[string]string
+	TLS     TLSConfig
+}
+
+// TLSConfig configures HTTPS connections to external-service. All fields are
+// optional; the system roots are used when CAFile is empty.
+type TLSConfig struct {
+	CAFile             string
+	CertFile           string
+	KeyFile            string
+	InsecureSkipVerify bool
+}
+
+// ClientConfigFrom builds a ClientConfig from the application configuration.
+// A configured token is sent as "Authorization: Bearer <token>".
+func ClientConfigFrom(cfg config.ExternalConfig) ClientConfig {
+	headers := make(map[string]string, len(cfg.Headers)+1)
+	for name, value := range cfg.Headers {
+		headers[name] = value
+	}
+	if cfg.Token != "" {
+		headers["Authorization"] = "Bearer " + cfg.Token
+	}
+	return ClientConfig{
+		Backend: cfg.Backend,
+		BaseURL: cfg.URL,
+		Timeout: cfg.Timeout,
+		Headers: headers,
+		TLS: TLSConfig{
+			CAFile:             cfg.CAFile,
+			CertFile:           cfg.CertFile,
+			KeyFile:            cfg.KeyFile,
+			InsecureSkipVerify: cfg.InsecureSkipVerify,
+		},
+	}
+}
+
+// BreakerConfigFrom builds the BreakerConfig from the application
+// configuration.
+func BreakerConfigFrom(cfg config.ExternalConfig) BreakerConfig {
+	return BreakerConfig{Threshold: cfg.BreakerThreshold, OpenTimeout: cfg.BreakerOpenTimeout}
+}
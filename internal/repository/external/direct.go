@@ -0,0 +1,39 @@
+package external
+
+import (
+	"context"
+	"time"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/config"
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/introspect"
+)
+
+// directClient introspects the target database from this process instead of
+// calling external-service. Both run the same introspect code, so they return
+// identical summaries for the same database.
+type directClient struct {
+	timeout time.Duration
+}
+
+// NewDirectClient returns a SummaryClient that connects to target databases
+// itself. timeout bounds each summary; zero means
+// config.DefaultExternalTimeout.
+func NewDirectClient(timeout time.Duration) SummaryClient {
+	if timeout <= 0 {
+		timeout = config.DefaultExternalTimeout
+	}
+	return &directClient{timeout: timeout}
+}
+
+func (c *directClient) FetchSummary(ctx context.Context, details domain.ConnectionDetails) (domain.Summary, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return introspect.Summarize(ctx, details)
+}
+
+func (c *directClient) ListDatabases(ctx context.Context, details domain.ConnectionDetails) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return introspect.Databases(ctx, details)
+}
@@ -0,0 +1,44 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/introspect"
+)
+
+// StatusError is returned when external-service answers with a status other
+// than 200 OK.
+type StatusError struct {
+	StatusCode int
+	Status     string
+	// Body holds the start of the response body, usually a JSON error.
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("external service: %s: %s", e.Status, e.Body)
+}
+
+// Retryable reports whether a SummaryClient error is worth retrying.
+// Timeouts, network failures, 5xx, 408 and 429 responses are transient.
+// Other 4xx responses, including external-service rejecting the target's
+// credentials, and logins the target refused are not: repeating them would
+// only lock out the account. Neither is ErrCircuitOpen, which only clears
+// once the breaker lets a probe through.
+func Retryable(err error) bool {
+	var status *StatusError
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, ErrCircuitOpen):
+		return false
+	case errors.Is(err, introspect.ErrRejected):
+		return false
+	case errors.As(err, &status):
+		return status.StatusCode >= 500 ||
+			status.StatusCode == http.StatusRequestTimeout ||
+			status.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
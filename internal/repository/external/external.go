@@ -1,45 +1,144 @@
 package external
 
 import (
-	"fmt"
-	"net/http"
+	"bytes"
 	"context"
-	"time"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
-	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/config"
 	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
 )
 
 type SummaryClient interface {
-	FetchSummary(details domain.ConnectionDetails) (domain.Summary, error)
+	FetchSummary(ctx context.Context, details domain.ConnectionDetails) (domain.Summary, error)
+	// ListDatabases returns the databases on the server addressed by
+	// details, excluding templates. DBName is optional.
+	ListDatabases(ctx context.Context, details domain.ConnectionDetails) ([]string, error)
 }
 
-type summaryClient struct{}
+// external-service endpoints.
+const (
+	// summaryPath introspects a database.
+	summaryPath = "/summarypostgres"
+	// databasesPath lists the databases of a server.
+	databasesPath = "/databases"
+)
 
-func NewSummaryClient() SummaryClient {
-	return &summaryClient{}
+type summaryClient struct {
+	base    string
+	headers map[string]string
+	http    *http.Client
 }
 
-func (c *summaryClient) FetchSummary(details domain.ConnectionDetails) (domain.Summary, error) {
-	_, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-defer cancel()
-    jsonData, err := json.Marshal(details)
-	fmt.Println("Connection details JSON:", string(jsonData))
-	req := fmt.Sprintf("http://127.0.0.1:8000/summarypostgres")
-    res, err := http.Post(req, "application/json", bytes.NewBuffer(jsonData))
+// NewSummaryClient returns the SummaryClient selected by cfg.Backend: an
+// HTTP client for external-service by default, or NewDirectClient. It fails
+// when the base URL is invalid or the TLS files cannot be loaded.
+func NewSummaryClient(cfg ClientConfig) (SummaryClient, error) {
+	if cfg.Backend == BackendDirect {
+		return NewDirectClient(cfg.Timeout), nil
+	}
+
+	base, err := url.Parse(cfg.BaseURL)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("invalid external service URL %q", cfg.BaseURL)
+	}
+
+	tlsConfig, err := cfg.TLS.load()
 	if err != nil {
-		return domain.Summary{}, err
+		return nil, err
 	}
-	defer res.Body.Close()
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
 
-	if res.StatusCode != http.StatusOK {
-		return domain.Summary{}, fmt.Errorf("failed to fetch summary: %s", res.Status)
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = config.DefaultExternalTimeout
 	}
 
+	return &summaryClient{
+		base:    strings.TrimSuffix(base.String(), "/"),
+		headers: cfg.Headers,
+		http:    &http.Client{Transport: transport, Timeout: timeout},
+	}, nil
+}
+
+// FetchSummary asks external-service to introspect the database described by
+// details. The request is abandoned when ctx is cancelled.
+func (c *summaryClient) FetchSummary(ctx context.Context, details domain.ConnectionDetails) (domain.Summary, error) {
 	var summary domain.Summary
-	if err := json.NewDecoder(res.Body).Decode(&summary); err != nil {
+	if err := c.post(ctx, summaryPath, details, &summary); err != nil {
 		return domain.Summary{}, err
 	}
-    fmt.Println("Fetched summary:", summary)
 	return summary, nil
 }
+
+func (c *summaryClient) ListDatabases(ctx context.Context, details domain.ConnectionDetails) ([]string, error) {
+	var databases []string
+	if err := c.post(ctx, databasesPath, details, &databases); err != nil {
+		return nil, err
+	}
+	return databases, nil
+}
+
+// post sends details as JSON to path and decodes the 200 response into out.
+// Other statuses are returned as a *StatusError.
+func (c *summaryClient) post(ctx context.Context, path string, details domain.ConnectionDetails, out interface{}) error {
+	jsonData, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.base+path, bytes.NewReader(jsonData))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return &StatusError{StatusCode: res.StatusCode, Status: res.Status, Body: strings.TrimSpace(string(body))}
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+func (t TLSConfig) load() (*tls.Config, error) {
+	config := &tls.Config{InsecureSkipVerify: t.InsecureSkipVerify}
+
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", t.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if t.CertFile != "" || t.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
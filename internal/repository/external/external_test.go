@@ -0,0 +1,138 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/config"
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/introspect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchSummary_SendsConfiguredRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/prefix/summarypostgres", r.URL.Path)
+		assert.Equal(t, "Bearer t0ken", r.Header.Get("Authorization"))
+		assert.Equal(t, "blue", r.Header.Get("X-Team"))
+
+		var details domain.ConnectionDetails
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&details))
+		assert.Equal(t, "secret", details.Password)
+
+		json.NewEncoder(w).Encode(domain.Summary{ID: "sum-1", Name: details.DBName})
+	}))
+	defer server.Close()
+
+	client, err := NewSummaryClient(ClientConfig{
+		BaseURL: server.URL + "/prefix/",
+		Headers: map[string]string{"Authorization": "Bearer t0ken", "X-Team": "blue"},
+	})
+	assert.NoError(t, err)
+
+	summary, err := client.FetchSummary(context.Background(), domain.ConnectionDetails{Host: "db", DBName: "demo", Password: "secret"})
+	assert.NoError(t, err)
+	assert.Equal(t, "sum-1", summary.ID)
+	assert.Equal(t, "demo", summary.Name)
+}
+
+func TestListDatabases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/databases", r.URL.Path)
+		var details domain.ConnectionDetails
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&details))
+		assert.Equal(t, "pg1", details.Host)
+
+		json.NewEncoder(w).Encode([]string{"billing", "sales"})
+	}))
+	defer server.Close()
+
+	client, err := NewSummaryClient(ClientConfig{BaseURL: server.URL})
+	assert.NoError(t, err)
+
+	databases, err := client.ListDatabases(context.Background(), domain.ConnectionDetails{Host: "pg1", User: "dba"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"billing", "sales"}, databases)
+}
+
+func TestFetchSummary_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "cannot connect to target database", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client, _ := NewSummaryClient(ClientConfig{BaseURL: server.URL})
+	_, err := client.FetchSummary(context.Background(), domain.ConnectionDetails{Host: "db"})
+	assert.ErrorContains(t, err, "502")
+	assert.ErrorContains(t, err, "cannot connect to target database")
+
+	var status *StatusError
+	assert.ErrorAs(t, err, &status)
+	assert.Equal(t, http.StatusBadGateway, status.StatusCode)
+	assert.True(t, Retryable(err))
+}
+
+func TestRetryable(t *testing.T) {
+	assert.True(t, Retryable(context.DeadlineExceeded))
+	assert.True(t, Retryable(&StatusError{StatusCode: http.StatusTooManyRequests}))
+	assert.False(t, Retryable(&StatusError{StatusCode: http.StatusForbidden}))
+	assert.False(t, Retryable(&StatusError{StatusCode: http.StatusUnauthorized}))
+	assert.False(t, Retryable(context.Canceled))
+	assert.False(t, Retryable(fmt.Errorf("%w: %w: boom", introspect.ErrConnect, introspect.ErrRejected)))
+	assert.True(t, Retryable(fmt.Errorf("%w: connection refused", introspect.ErrConnect)))
+}
+
+func TestFetchSummary_HonoursContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, _ := NewSummaryClient(ClientConfig{BaseURL: server.URL})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.FetchSummary(ctx, domain.ConnectionDetails{Host: "db"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestNewSummaryClient_InvalidConfig(t *testing.T) {
+	_, err := NewSummaryClient(ClientConfig{BaseURL: "127.0.0.1:8000"})
+	assert.Error(t, err)
+
+	_, err = NewSummaryClient(ClientConfig{BaseURL: config.DefaultExternalURL, TLS: TLSConfig{CAFile: "/does/not/exist.pem"}})
+	assert.Error(t, err)
+}
+
+func TestNewSummaryClient_SelectsBackend(t *testing.T) {
+	client, err := NewSummaryClient(ClientConfig{Backend: BackendDirect, BaseURL: "not a url"})
+	assert.NoError(t, err)
+	assert.IsType(t, &directClient{}, client)
+
+	client, err = NewSummaryClient(ClientConfig{BaseURL: config.DefaultExternalURL})
+	assert.NoError(t, err)
+	assert.IsType(t, &summaryClient{}, client)
+}
+
+func TestClientConfigFrom(t *testing.T) {
+	cfg := ClientConfigFrom(config.ExternalConfig{
+		Backend: BackendHTTP,
+		URL:     "http://external:8000",
+		Timeout: 90 * time.Second,
+		Token:   "t0ken",
+		Headers: map[string]string{"X-Team": "blue"},
+		CAFile:  "/etc/ca.pem",
+	})
+	assert.Equal(t, "http://external:8000", cfg.BaseURL)
+	assert.Equal(t, 90*time.Second, cfg.Timeout)
+	assert.Equal(t, map[string]string{"Authorization": "Bearer t0ken", "X-Team": "blue"}, cfg.Headers)
+	assert.Equal(t, "/etc/ca.pem", cfg.TLS.CAFile)
+}
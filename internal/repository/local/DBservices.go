@@ -1,7 +1,11 @@
 package local
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
@@ -10,70 +14,433 @@ import (
 
 type SummaryRepository interface {
 	SaveSummary(summary *domain.Summary) error
-	GetSummaries(page, pageSize int) ([]domain.Summary, error)
+	GetSummaries(query domain.SummaryQuery, page, pageSize int) (*domain.SummaryPage, error)
 	GetSummaryByID(id string) (*domain.Summary, error)
+	GetSnapshots(sourceID string, page, pageSize int) ([]domain.Summary, error)
+	GetSnapshot(sourceID, id string) (*domain.Summary, error)
+	DeleteSummary(id string) (bool, error)
+	RestoreSummary(id string) (bool, error)
+	PurgeSummary(id string) (bool, error)
+	PurgeSummariesBefore(cutoff time.Time) (int64, error)
+	GetSchemas(summaryID string, order domain.ListOrder, page, pageSize int) (*domain.SchemaPage, error)
+	GetTables(summaryID, schemaName string, order domain.ListOrder, page, pageSize int) (*domain.TablePage, error)
+	GetTable(summaryID, schemaName, tableName string) (*domain.Table, error)
 }
 
 type summaryRepo struct{}
 
+// IsNotFound reports whether err means the requested record does not exist.
+func IsNotFound(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound)
+}
+
 func NewSummaryRepository() SummaryRepository {
 	return &summaryRepo{}
 }
 
+// preloadTree eager-loads the full Schema/Table tree of a summary together
+// with every per-table child entity.
+func preloadTree(db *gorm.DB) *gorm.DB {
+	return preloadTableChildren(db, "Schemas.Tables.")
+}
+
+// preloadTableChildren eager-loads the columns, indexes and constraints of
+// the tables at path, which is empty when loading tables directly.
+func preloadTableChildren(db *gorm.DB, path string) *gorm.DB {
+	return db.
+		Preload(path+"Columns", func(db *gorm.DB) *gorm.DB {
+			return db.Order("ordinal_position")
+		}).
+		Preload(path+"Indexes", func(db *gorm.DB) *gorm.DB {
+			return db.Order("name")
+		}).
+		Preload(path+"Constraints", func(db *gorm.DB) *gorm.DB {
+			return db.Order("name")
+		})
+}
 
-func (r *summaryRepo) SaveSummary(summary *domain.Summary) error {
+// assignIDs gives every child entity an ID and links it to its parent so the
+// whole tree can be written with a single Create.
+func assignIDs(summary *domain.Summary) {
 	for i := range summary.Schemas {
-		if summary.Schemas[i].ID == "" {
-			summary.Schemas[i].ID = uuid.NewString()
+		schema := &summary.Schemas[i]
+		if schema.ID == "" {
+			schema.ID = uuid.NewString()
 		}
-		summary.Schemas[i].SummaryID = summary.ID
+		schema.SummaryID = summary.ID
+
+		for j := range schema.Tables {
+			table := &schema.Tables[j]
+			if table.ID == "" {
+				table.ID = uuid.NewString()
+			}
+			table.SchemaID = schema.ID
+
+			for k := range table.Columns {
+				column := &table.Columns[k]
+				if column.ID == "" {
+					column.ID = uuid.NewString()
+				}
+				column.TableID = table.ID
+			}
+
+			for k := range table.Indexes {
+				index := &table.Indexes[k]
+				if index.ID == "" {
+					index.ID = uuid.NewString()
+				}
+				index.TableID = table.ID
+			}
 
-		for j := range summary.Schemas[i].Tables {
-			if summary.Schemas[i].Tables[j].ID == "" {
-				summary.Schemas[i].Tables[j].ID = uuid.NewString()
+			for k := range table.Constraints {
+				constraint := &table.Constraints[k]
+				if constraint.ID == "" {
+					constraint.ID = uuid.NewString()
+				}
+				constraint.TableID = table.ID
 			}
-			summary.Schemas[i].Tables[j].SchemaID = summary.Schemas[i].ID
 		}
 	}
+}
 
-	var existing domain.Summary
-	err := dB.Preload("Schemas.Tables").First(&existing, "id = ?", summary.ID).Error
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return dB.Create(summary).Error
+// ErrSummaryExists is returned by SaveSummary when a summary with the same ID
+// is already stored.
+var ErrSummaryExists = errors.New("summary already exists")
+
+// SaveSummary stores summary as a new immutable snapshot of its source
+// database, creating the Source record on first sync. A summary without an ID
+// gets domain.SnapshotID of its source and SyncedAt. Saving an ID that
+// already exists fails with ErrSummaryExists rather than overwriting the
+// earlier snapshot.
+func (r *summaryRepo) SaveSummary(summary *domain.Summary) error {
+	return dB.Transaction(func(tx *gorm.DB) error {
+		source, err := findOrCreateSource(tx, summary.SourceInfo)
+		if err != nil {
+			return err
 		}
-		return err
+		summary.SourceID = source.ID
+		if summary.ID == "" {
+			summary.ID = domain.SnapshotID(source.ID, summary.SyncedAt)
+		}
+
+		// Soft-deleted snapshots still own their ID until they are purged.
+		var existing int64
+		if err := tx.Unscoped().Model(&domain.Summary{}).Where("id = ?", summary.ID).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return fmt.Errorf("%w: %s", ErrSummaryExists, summary.ID)
+		}
+
+		assignIDs(summary)
+		if err := tx.Create(summary).Error; err != nil {
+			return err
+		}
+		return tx.Model(source).Update("last_synced_at", summary.SyncedAt).Error
+	})
+}
+
+// Correlated subqueries over the tables of the summary being filtered. Sizes
+// are summed as numeric so the total does not depend on the summation order
+// and compares exactly against the value stored in a cursor.
+const (
+	summaryTables     = "FROM tables JOIN schemas ON schemas.id = tables.schema_id WHERE schemas.summary_id = summaries.id"
+	summaryTotalSize  = "(SELECT COALESCE(SUM(tables.size_mb::numeric), 0) " + summaryTables + ")"
+	summaryTableCount = "(SELECT COUNT(*) " + summaryTables + ")"
+)
+
+// summaryOrders maps SummaryQuery.Sort keys to ORDER BY expressions.
+var summaryOrders = map[string]string{
+	domain.SortSyncedAt:   "summaries.synced_at",
+	domain.SortTotalSize:  summaryTotalSize,
+	domain.SortTableCount: summaryTableCount,
+}
+
+// filterSummaries applies the filters and search of query.
+func filterSummaries(db *gorm.DB, query domain.SummaryQuery) *gorm.DB {
+	if query.Host != "" {
+		db = db.Where("summaries.source_host = ?", query.Host)
+	}
+	if query.DBName != "" {
+		db = db.Where("summaries.source_db_name = ?", query.DBName)
+	}
+	if query.SyncedAfter != nil {
+		db = db.Where("summaries.synced_at >= ?", *query.SyncedAfter)
+	}
+	if query.SyncedBefore != nil {
+		db = db.Where("summaries.synced_at < ?", *query.SyncedBefore)
+	}
+	if query.HasTable != "" {
+		db = db.Where("EXISTS (SELECT 1 "+summaryTables+" AND tables.name = ?)", query.HasTable)
+	}
+	if query.MinSizeMB > 0 {
+		db = db.Where(summaryTotalSize+" >= ?", query.MinSizeMB)
+	}
+	if query.Q != "" {
+		pattern := "%" + escapeLike(query.Q) + "%"
+		db = db.Where(
+			"(EXISTS (SELECT 1 FROM schemas WHERE schemas.summary_id = summaries.id AND schemas.name ILIKE ?) OR "+
+				"EXISTS (SELECT 1 "+summaryTables+" AND tables.name ILIKE ?))",
+			pattern, pattern)
 	}
+	return db
+}
 
-	return dB.Session(&gorm.Session{FullSaveAssociations: true}).Updates(summary).Error
+// escapeLike escapes the LIKE wildcards in s so it matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
 
+// GetSummaries lists a page of the summaries matching query. Pages continue
+// after query.After using keyset pagination on (sort value, ID); without a
+// cursor, page selects the page by offset. One extra row is fetched to tell
+// whether a next page exists.
+func (r *summaryRepo) GetSummaries(query domain.SummaryQuery, page, pageSize int) (*domain.SummaryPage, error) {
+	if query.Sort == "" {
+		query.Sort = domain.SortSyncedAt
+	}
+	order, ok := summaryOrders[query.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unknown sort %q", query.Sort)
+	}
+	direction, after := " ASC", ">"
+	if query.Desc {
+		direction, after = " DESC", "<"
+	}
+
+	result := &domain.SummaryPage{Items: []domain.SummaryListItem{}}
+	if err := filterSummaries(dB.Model(&domain.Summary{}), query).Count(&result.Total).Error; err != nil {
+		return nil, err
+	}
 
-func (r *summaryRepo) GetSummaries(page, pageSize int) ([]domain.Summary, error) {
-    var summaries []domain.Summary
-    offset := (page - 1) * pageSize
+	list := filterSummaries(dB.Model(&domain.Summary{}), query)
+	if c := query.After; c != nil {
+		if query.Sort == domain.SortSyncedAt {
+			list = list.Where("("+order+", summaries.id) "+after+" (?, ?)", c.SyncedAt, c.ID)
+		} else {
+			list = list.Where("("+order+", summaries.id) "+after+" (?::numeric, ?)", c.Value, c.ID)
+		}
+	} else {
+		list = list.Offset((page - 1) * pageSize)
+	}
+	if !query.Compact {
+		list = list.Preload("Schemas.Tables")
+	}
+
+	var summaries []domain.Summary
+	err := list.
+		Order(order + direction).
+		Order("summaries.id" + direction).
+		Limit(pageSize + 1).
+		Find(&summaries).Error
+	if err != nil {
+		return nil, err
+	}
+	more := len(summaries) > pageSize
+	if more {
+		summaries = summaries[:pageSize]
+	}
 
-    
-    err := dB.
-        Preload("Schemas.Tables").
-        Limit(pageSize).
-        Offset(offset).
-        Find(&summaries).Error
+	totals, err := summaryTotals(summaries)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range summaries {
+		t := totals[s.ID]
+		result.Items = append(result.Items, domain.SummaryListItem{
+			ID:          s.ID,
+			SourceID:    s.SourceID,
+			Name:        s.Name,
+			SyncedAt:    s.SyncedAt,
+			SourceInfo:  s.SourceInfo,
+			SchemaCount: t.SchemaCount,
+			TableCount:  t.TableCount,
+			TotalSizeMB: t.TotalSizeMB,
+			Schemas:     s.Schemas,
+		})
+	}
 
-    if err != nil {
-        return nil, err
-    }
+	if more {
+		last := result.Items[len(result.Items)-1]
+		cursor := domain.SummaryCursor{Sort: query.Sort, Desc: query.Desc, ID: last.ID}
+		switch query.Sort {
+		case domain.SortSyncedAt:
+			cursor.SyncedAt = last.SyncedAt
+		case domain.SortTotalSize:
+			// A summary without schemas has no totals row.
+			cursor.Value = totals[last.ID].TotalSize
+			if cursor.Value == "" {
+				cursor.Value = "0"
+			}
+		case domain.SortTableCount:
+			cursor.Value = strconv.Itoa(last.TableCount)
+		}
+		result.NextCursor = cursor.Encode()
+	}
+	return result, nil
+}
 
-    return summaries, nil
+type summaryTotal struct {
+	SummaryID   string
+	SchemaCount int
+	TableCount  int
+	TotalSizeMB float64
+	// TotalSize is TotalSizeMB as exact numeric text, for cursors.
+	TotalSize string
 }
 
+// summaryTotals aggregates the schema count, table count and total table
+// size of each summary in one query, keyed by summary ID.
+func summaryTotals(summaries []domain.Summary) (map[string]summaryTotal, error) {
+	totals := make(map[string]summaryTotal, len(summaries))
+	if len(summaries) == 0 {
+		return totals, nil
+	}
+	ids := make([]string, len(summaries))
+	for i, s := range summaries {
+		ids[i] = s.ID
+	}
+
+	var rows []summaryTotal
+	err := dB.Table("schemas").
+		Select("schemas.summary_id, COUNT(DISTINCT schemas.id) AS schema_count, COUNT(tables.id) AS table_count, COALESCE(SUM(tables.size_mb::numeric), 0) AS total_size_mb, COALESCE(SUM(tables.size_mb::numeric), 0)::text AS total_size").
+		Joins("LEFT JOIN tables ON tables.schema_id = schemas.id").
+		Where("schemas.summary_id IN ?", ids).
+		Group("schemas.summary_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		totals[row.SummaryID] = row
+	}
+	return totals, nil
+}
 
 func (r *summaryRepo) GetSummaryByID(id string) (*domain.Summary, error) {
-    var summary domain.Summary
-    if err := dB.Preload("Schemas.Tables").First(&summary, "id = ?", id).Error; err != nil {
-        return nil, err
-    }
-	fmt.Println("Retrieved summary:", summary)
-    return &summary, nil
+	var summary domain.Summary
+	if err := preloadTree(dB).First(&summary, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// GetSnapshots lists the snapshots of a source, newest first. Only the
+// summary headers are loaded; fetch a single snapshot for the full tree.
+func (r *summaryRepo) GetSnapshots(sourceID string, page, pageSize int) ([]domain.Summary, error) {
+	var summaries []domain.Summary
+	err := dB.
+		Where("source_id = ?", sourceID).
+		Order("synced_at DESC").
+		Limit(pageSize).
+		Offset((page - 1) * pageSize).
+		Find(&summaries).Error
+	if err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+// GetSnapshot returns a snapshot of the given source with its full tree, or
+// nil when no such snapshot exists.
+func (r *summaryRepo) GetSnapshot(sourceID, id string) (*domain.Summary, error) {
+	var summary domain.Summary
+	err := preloadTree(dB).First(&summary, "id = ? AND source_id = ?", id, sourceID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// DeleteSummary soft-deletes a summary, reporting whether a live summary
+// with that ID existed. The snapshot stays stored until it is purged.
+func (r *summaryRepo) DeleteSummary(id string) (bool, error) {
+	result := dB.Delete(&domain.Summary{}, "id = ?", id)
+	return result.RowsAffected > 0, result.Error
+}
+
+// RestoreSummary undoes DeleteSummary, reporting whether a deleted summary
+// with that ID existed.
+func (r *summaryRepo) RestoreSummary(id string) (bool, error) {
+	result := dB.Unscoped().
+		Model(&domain.Summary{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	return result.RowsAffected > 0, result.Error
+}
+
+// PurgeSummary permanently removes a summary, deleted or not, together with
+// its schemas, tables, columns, indexes and constraints. It reports whether
+// the summary existed.
+func (r *summaryRepo) PurgeSummary(id string) (bool, error) {
+	var purged int64
+	err := dB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		purged, err = purgeSummaries(tx, []string{id})
+		return err
+	})
+	return purged > 0, err
 }
 
+// purgeBatchSize bounds how many summaries one retention transaction
+// removes.
+const purgeBatchSize = 500
+
+// PurgeSummariesBefore permanently removes every summary synced before
+// cutoff, and every soft-deleted summary deleted before it, returning how
+// many were removed. The newest live snapshot of each source is kept however
+// old it is, so a source synced less often than the retention period does
+// not disappear. Summaries are purged in batches of purgeBatchSize.
+func (r *summaryRepo) PurgeSummariesBefore(cutoff time.Time) (int64, error) {
+	latest := dB.Model(&domain.Summary{}).
+		Select("DISTINCT ON (source_id) id").
+		Order("source_id, synced_at DESC, id DESC")
+
+	var total int64
+	for {
+		var purged int64
+		err := dB.Transaction(func(tx *gorm.DB) error {
+			var ids []string
+			err := tx.Unscoped().
+				Model(&domain.Summary{}).
+				Where("deleted_at < ? OR (synced_at < ? AND id NOT IN (?))", cutoff, cutoff, latest).
+				Order("synced_at, id").
+				Limit(purgeBatchSize).
+				Pluck("id", &ids).Error
+			if err != nil || len(ids) == 0 {
+				return err
+			}
+			purged, err = purgeSummaries(tx, ids)
+			return err
+		})
+		total += purged
+		if err != nil || purged < purgeBatchSize {
+			return total, err
+		}
+	}
+}
+
+// purgeSummaries hard-deletes the summaries with the given IDs and their
+// whole tree, children first, returning how many summaries were removed.
+// Sync jobs keep their summary ID as a historical reference.
+func purgeSummaries(tx *gorm.DB, ids []string) (int64, error) {
+	schemas := tx.Model(&domain.Schema{}).Select("id").Where("summary_id IN ?", ids)
+	tables := tx.Model(&domain.Table{}).Select("id").Where("schema_id IN (?)", schemas)
+
+	for _, child := range []interface{}{&domain.Column{}, &domain.Index{}, &domain.Constraint{}} {
+		if err := tx.Where("table_id IN (?)", tables).Delete(child).Error; err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Where("schema_id IN (?)", schemas).Delete(&domain.Table{}).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Where("summary_id IN ?", ids).Delete(&domain.Schema{}).Error; err != nil {
+		return 0, err
+	}
+	result := tx.Unscoped().Where("id IN ?", ids).Delete(&domain.Summary{})
+	return result.RowsAffected, result.Error
+}
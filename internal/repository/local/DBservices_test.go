@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
 	"github.com/stretchr/testify/assert"
@@ -28,15 +29,20 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	}
 
 
-	err = db.Migrator().DropTable(&domain.Summary{})
+	models := []interface{}{&domain.Column{}, &domain.Index{}, &domain.Constraint{}, &domain.Table{}, &domain.Schema{}, &domain.Summary{}, &domain.Source{}, &domain.Server{}, &domain.SyncJob{}, &domain.Schedule{}}
+
+	err = db.Migrator().DropTable(models...)
 	if err != nil {
 		t.Fatalf("failed to drop schema: %v", err)
 	}
 
-	err = db.AutoMigrate(&domain.Summary{})
+	err = db.AutoMigrate(models...)
 	if err != nil {
 		t.Fatalf("failed to migrate schema: %v", err)
 	}
+	if err := ensureSearchIndexes(db); err != nil {
+		t.Fatalf("failed to create search indexes: %v", err)
+	}
 
 	
 	dB = db
@@ -71,20 +77,164 @@ func TestSaveSummary_NewRecord(t *testing.T) {
 	assert.Equal(t, "123", found.ID)
 }
 
-func TestSaveSummary_UpdateRecord(t *testing.T) {
+func TestSaveSummary_KeepsHistory(t *testing.T) {
 	setupTestDB(t)
 	repo := NewSummaryRepository()
 
-	summary := &domain.Summary{ID: "123", SourceInfo: domain.ConnectionDetails{Host: "localhost"}}
-	_ = dB.Create(summary).Error
+	port := 5432
+	details := domain.ConnectionDetails{Host: "localhost", Port: &port, User: "test", DBName: "demo"}
+
+	first := &domain.Summary{ID: "snap-1", SourceInfo: details, SyncedAt: time.Now().Add(-time.Hour)}
+	assert.NoError(t, repo.SaveSummary(first))
+
+	second := &domain.Summary{ID: "snap-2", SourceInfo: details, SyncedAt: time.Now()}
+	assert.NoError(t, repo.SaveSummary(second))
+
+	assert.NotEmpty(t, first.SourceID)
+	assert.Equal(t, first.SourceID, second.SourceID)
+
+	var count int64
+	dB.Model(&domain.Source{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	// Existing snapshots are immutable.
+	err := repo.SaveSummary(&domain.Summary{ID: "snap-1", SourceInfo: details})
+	assert.Error(t, err)
+}
+
+func TestGetSnapshots(t *testing.T) {
+	setupTestDB(t)
+	repo := NewSummaryRepository()
+
+	details := domain.ConnectionDetails{Host: "localhost", DBName: "demo"}
+	other := domain.ConnectionDetails{Host: "localhost", DBName: "other"}
+	now := time.Now()
+	for i := 1; i <= 3; i++ {
+		s := &domain.Summary{ID: fmt.Sprintf("demo-%d", i), SourceInfo: details, SyncedAt: now.Add(time.Duration(i) * time.Minute)}
+		assert.NoError(t, repo.SaveSummary(s))
+	}
+	assert.NoError(t, repo.SaveSummary(&domain.Summary{ID: "other-1", SourceInfo: other, SyncedAt: now}))
+
+	latest, err := repo.GetSummaryByID("demo-3")
+	assert.NoError(t, err)
+
+	snapshots, err := repo.GetSnapshots(latest.SourceID, 1, 10)
+	assert.NoError(t, err)
+	assert.Len(t, snapshots, 3)
+	assert.Equal(t, "demo-3", snapshots[0].ID)
+	assert.Equal(t, "demo-1", snapshots[2].ID)
+
+	found, err := repo.GetSnapshot(latest.SourceID, "demo-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "demo-1", found.ID)
+
+	missing, err := repo.GetSnapshot(latest.SourceID, "other-1")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestSaveSummary_PersistsColumns(t *testing.T) {
+	setupTestDB(t)
+	repo := NewSummaryRepository()
+
+	summary := &domain.Summary{
+		ID: "cols",
+		Schemas: []domain.Schema{{
+			Name: "public",
+			Tables: []domain.Table{{
+				Name: "orders",
+				Columns: []domain.Column{
+					{Name: "customer_id", OrdinalPosition: 2, DataType: "bigint"},
+					{Name: "id", OrdinalPosition: 1, DataType: "bigint", IsIdentity: true},
+				},
+			}},
+		}},
+	}
 
-	summary.SourceInfo.User = "updatedUser"
 	err := repo.SaveSummary(summary)
 	assert.NoError(t, err)
 
-	var found domain.Summary
-	_ = dB.First(&found, "id = ?", "123").Error
-	assert.Equal(t, "updatedUser", found.SourceInfo.User)
+	found, err := repo.GetSummaryByID("cols")
+	assert.NoError(t, err)
+	columns := found.Schemas[0].Tables[0].Columns
+	assert.Len(t, columns, 2)
+	assert.Equal(t, "id", columns[0].Name)
+	assert.True(t, columns[0].IsIdentity)
+	assert.Equal(t, "customer_id", columns[1].Name)
+}
+
+func TestSaveSummary_PersistsIndexes(t *testing.T) {
+	setupTestDB(t)
+	repo := NewSummaryRepository()
+
+	predicate := "deleted_at IS NULL"
+	summary := &domain.Summary{
+		ID: "idx",
+		Schemas: []domain.Schema{{
+			Name: "public",
+			Tables: []domain.Table{{
+				Name: "orders",
+				Indexes: []domain.Index{
+					{Name: "orders_pkey", Columns: []string{"id"}, IsUnique: true, IsPrimary: true, Method: "btree", Scans: 42},
+					{Name: "orders_customer_idx", Columns: []string{"customer_id", "lower(email)"}, Predicate: &predicate, Method: "btree"},
+				},
+			}},
+		}},
+	}
+
+	err := repo.SaveSummary(summary)
+	assert.NoError(t, err)
+
+	found, err := repo.GetSummaryByID("idx")
+	assert.NoError(t, err)
+	indexes := found.Schemas[0].Tables[0].Indexes
+	assert.Len(t, indexes, 2)
+	assert.Equal(t, "orders_customer_idx", indexes[0].Name)
+	assert.Equal(t, []string{"customer_id", "lower(email)"}, indexes[0].Columns)
+	assert.Equal(t, predicate, *indexes[0].Predicate)
+	assert.Equal(t, int64(42), indexes[1].Scans)
+}
+
+func TestSaveSummary_PersistsConstraints(t *testing.T) {
+	setupTestDB(t)
+	repo := NewSummaryRepository()
+
+	refSchema, refTable, cascade := "public", "customers", "CASCADE"
+	summary := &domain.Summary{
+		ID: "con",
+		Schemas: []domain.Schema{{
+			Name: "public",
+			Tables: []domain.Table{{
+				Name: "orders",
+				Constraints: []domain.Constraint{
+					{Name: "orders_pkey", Type: domain.ConstraintPrimaryKey, Columns: []string{"id"}},
+					{
+						Name:              "orders_customer_id_fkey",
+						Type:              domain.ConstraintForeignKey,
+						Columns:           []string{"customer_id"},
+						ReferencedSchema:  &refSchema,
+						ReferencedTable:   &refTable,
+						ReferencedColumns: []string{"id"},
+						OnDelete:          &cascade,
+					},
+				},
+			}},
+		}},
+	}
+
+	err := repo.SaveSummary(summary)
+	assert.NoError(t, err)
+
+	found, err := repo.GetSummaryByID("con")
+	assert.NoError(t, err)
+	constraints := found.Schemas[0].Tables[0].Constraints
+	assert.Len(t, constraints, 2)
+	fk := constraints[0]
+	assert.Equal(t, domain.ConstraintForeignKey, fk.Type)
+	assert.Equal(t, "customers", *fk.ReferencedTable)
+	assert.Equal(t, []string{"id"}, fk.ReferencedColumns)
+	assert.Equal(t, "CASCADE", *fk.OnDelete)
+	assert.Equal(t, domain.ConstraintPrimaryKey, constraints[1].Type)
 }
 
 func TestGetSummaries(t *testing.T) {
@@ -96,9 +246,11 @@ func TestGetSummaries(t *testing.T) {
 		_ = dB.Create(&s).Error
 	}
 
-	summaries, err := repo.GetSummaries(1, 2)
+	page, err := repo.GetSummaries(domain.SummaryQuery{}, 1, 2)
 	assert.NoError(t, err)
-	assert.Len(t, summaries, 2)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, int64(5), page.Total)
+	assert.NotEmpty(t, page.NextCursor)
 }
 
 func TestGetSummaryByID(t *testing.T) {
@@ -116,3 +268,456 @@ func TestGetSummaryByID(t *testing.T) {
 	_, err = repo.GetSummaryByID("notfound")
 	assert.Error(t, err)
 }
+
+func TestRecordDiscovery_GroupsSources(t *testing.T) {
+	setupTestDB(t)
+	summaries := NewSummaryRepository()
+	servers := NewServerRepository()
+
+	port := 5432
+	for _, name := range []string{"sales", "billing", "unrelated"} {
+		host := "pg1"
+		if name == "unrelated" {
+			host = "pg2"
+		}
+		summary := &domain.Summary{SourceInfo: domain.ConnectionDetails{Host: host, Port: &port, DBName: name}}
+		assert.NoError(t, summaries.SaveSummary(summary))
+	}
+
+	server, err := servers.FindOrCreateServer("pg1", 5432)
+	assert.NoError(t, err)
+	again, err := servers.FindOrCreateServer("pg1", 5432)
+	assert.NoError(t, err)
+	assert.Equal(t, server.ID, again.ID)
+
+	assert.NoError(t, servers.RecordDiscovery(server.ID, time.Now(), "dba", []string{"sales", "billing", "fresh"}))
+
+	found, err := servers.GetServerByID(server.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, found.LastDiscoveredAt)
+	assert.Len(t, found.Sources, 3)
+	assert.Equal(t, "billing", found.Sources[0].DBName)
+	assert.Equal(t, "fresh", found.Sources[1].DBName, "databases not synced yet get a source")
+	assert.Equal(t, "dba", found.Sources[1].User)
+	assert.Equal(t, server.ID, *found.Sources[2].ServerID)
+
+	missing, err := servers.GetServerByID("missing")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestSaveSummary_DerivesIdentity(t *testing.T) {
+	setupTestDB(t)
+	repo := NewSummaryRepository()
+
+	syncedAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	sales := &domain.Summary{SyncedAt: syncedAt, SourceInfo: domain.ConnectionDetails{Host: "pg1", DBName: "sales"}}
+	billing := &domain.Summary{SyncedAt: syncedAt, SourceInfo: domain.ConnectionDetails{Host: "pg1", DBName: "billing"}}
+	assert.NoError(t, repo.SaveSummary(sales))
+	assert.NoError(t, repo.SaveSummary(billing))
+
+	assert.NotEqual(t, sales.ID, billing.ID)
+	assert.Equal(t, domain.SourceID("pg1", domain.DefaultPort, "sales"), sales.SourceID)
+	assert.Equal(t, domain.SnapshotID(sales.SourceID, syncedAt), sales.ID)
+
+	// A payload reusing a stored ID is rejected instead of overwriting it.
+	collision := &domain.Summary{ID: sales.ID, SyncedAt: syncedAt.Add(time.Hour), SourceInfo: domain.ConnectionDetails{Host: "pg2", DBName: "other"}}
+	assert.ErrorIs(t, repo.SaveSummary(collision), ErrSummaryExists)
+
+	stored, err := repo.GetSummaryByID(sales.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "sales", stored.SourceInfo.DBName)
+}
+
+func TestDeleteRestorePurgeSummary(t *testing.T) {
+	setupTestDB(t)
+	repo := NewSummaryRepository()
+
+	summary := &domain.Summary{
+		SyncedAt:   time.Now(),
+		SourceInfo: domain.ConnectionDetails{Host: "pg1", DBName: "sales"},
+		Schemas: []domain.Schema{{Name: "public", Tables: []domain.Table{{
+			Name:        "orders",
+			Columns:     []domain.Column{{Name: "id"}},
+			Indexes:     []domain.Index{{Name: "orders_pkey"}},
+			Constraints: []domain.Constraint{{Name: "orders_pkey", Type: domain.ConstraintPrimaryKey}},
+		}}}},
+	}
+	assert.NoError(t, repo.SaveSummary(summary))
+
+	deleted, err := repo.DeleteSummary(summary.ID)
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	_, err = repo.GetSummaryByID(summary.ID)
+	assert.True(t, IsNotFound(err))
+	snapshots, err := repo.GetSnapshots(summary.SourceID, 1, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, snapshots)
+	deleted, _ = repo.DeleteSummary(summary.ID)
+	assert.False(t, deleted, "already deleted")
+
+	restored, err := repo.RestoreSummary(summary.ID)
+	assert.NoError(t, err)
+	assert.True(t, restored)
+	found, err := repo.GetSummaryByID(summary.ID)
+	assert.NoError(t, err)
+	assert.Len(t, found.Schemas[0].Tables[0].Columns, 1)
+
+	purged, err := repo.PurgeSummary(summary.ID)
+	assert.NoError(t, err)
+	assert.True(t, purged)
+	for _, model := range []interface{}{&domain.Summary{}, &domain.Schema{}, &domain.Table{}, &domain.Column{}, &domain.Index{}, &domain.Constraint{}} {
+		var count int64
+		assert.NoError(t, dB.Unscoped().Model(model).Count(&count).Error)
+		assert.Zero(t, count, "%T left behind", model)
+	}
+}
+
+func TestPurgeSummariesBefore(t *testing.T) {
+	setupTestDB(t)
+	repo := NewSummaryRepository()
+
+	now := time.Now()
+	old := &domain.Summary{SyncedAt: now.AddDate(0, 0, -40), SourceInfo: domain.ConnectionDetails{Host: "pg1", DBName: "legacy"}}
+	oldSales := &domain.Summary{SyncedAt: now.AddDate(0, 0, -50), SourceInfo: domain.ConnectionDetails{Host: "pg1", DBName: "sales"}}
+	recent := &domain.Summary{SyncedAt: now.AddDate(0, 0, -1), SourceInfo: domain.ConnectionDetails{Host: "pg1", DBName: "sales"}}
+	rare := &domain.Summary{SyncedAt: now.AddDate(0, 0, -90), SourceInfo: domain.ConnectionDetails{Host: "pg2", DBName: "archive"}}
+	for _, s := range []*domain.Summary{old, oldSales, recent, rare} {
+		assert.NoError(t, repo.SaveSummary(s))
+	}
+	// Soft-deleted copies are still subject to retention.
+	_, err := repo.DeleteSummary(old.ID)
+	assert.NoError(t, err)
+
+	purged, err := repo.PurgeSummariesBefore(now.AddDate(0, 0, -30))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+
+	// The only snapshot of a rarely synced source survives.
+	var ids []string
+	assert.NoError(t, dB.Unscoped().Model(&domain.Summary{}).Order("id").Pluck("id", &ids).Error)
+	assert.ElementsMatch(t, []string{recent.ID, rare.ID}, ids)
+}
+
+func TestGetSummaries_FiltersAndSorts(t *testing.T) {
+	setupTestDB(t)
+	repo := NewSummaryRepository()
+
+	now := time.Now()
+	table := func(name string, size float64) domain.Table { return domain.Table{Name: name, SizeMB: size} }
+	sales := &domain.Summary{SyncedAt: now.Add(-2 * time.Hour), SourceInfo: domain.ConnectionDetails{Host: "pg1", DBName: "sales"},
+		Schemas: []domain.Schema{{Name: "public", Tables: []domain.Table{table("orders", 30), table("customers", 5)}}}}
+	billing := &domain.Summary{SyncedAt: now.Add(-time.Hour), SourceInfo: domain.ConnectionDetails{Host: "pg1", DBName: "billing"},
+		Schemas: []domain.Schema{{Name: "ledger", Tables: []domain.Table{table("invoices", 10)}}}}
+	audit := &domain.Summary{SyncedAt: now, SourceInfo: domain.ConnectionDetails{Host: "pg2", DBName: "audit"},
+		Schemas: []domain.Schema{{Name: "public", Tables: []domain.Table{table("events", 50), table("users", 1), table("sessions", 1)}}}}
+	for _, s := range []*domain.Summary{sales, billing, audit} {
+		assert.NoError(t, repo.SaveSummary(s))
+	}
+
+	ids := func(query domain.SummaryQuery) []string {
+		page, err := repo.GetSummaries(query, 1, 10)
+		assert.NoError(t, err)
+		var ids []string
+		for _, s := range page.Items {
+			ids = append(ids, s.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{audit.ID, billing.ID, sales.ID}, ids(domain.SummaryQuery{Desc: true}))
+	assert.Equal(t, []string{billing.ID, sales.ID}, ids(domain.SummaryQuery{Host: "pg1", Desc: true}))
+	assert.Equal(t, []string{sales.ID}, ids(domain.SummaryQuery{DBName: "sales"}))
+	after := now.Add(-90 * time.Minute)
+	assert.Equal(t, []string{billing.ID, audit.ID}, ids(domain.SummaryQuery{SyncedAfter: &after}))
+	assert.Equal(t, []string{sales.ID}, ids(domain.SummaryQuery{SyncedBefore: &after}))
+	assert.Equal(t, []string{billing.ID}, ids(domain.SummaryQuery{HasTable: "invoices"}))
+	assert.Equal(t, []string{sales.ID, audit.ID}, ids(domain.SummaryQuery{MinSizeMB: 35}))
+	assert.Equal(t, []string{billing.ID}, ids(domain.SummaryQuery{Q: "LEDG"}))
+	assert.Equal(t, []string{sales.ID, audit.ID}, ids(domain.SummaryQuery{Q: "ers"}))
+	assert.Empty(t, ids(domain.SummaryQuery{Q: "%"}))
+	assert.Equal(t, []string{audit.ID, sales.ID, billing.ID}, ids(domain.SummaryQuery{Sort: domain.SortTotalSize, Desc: true}))
+	assert.Equal(t, []string{billing.ID, sales.ID, audit.ID}, ids(domain.SummaryQuery{Sort: domain.SortTableCount}))
+}
+
+func TestGetSummaries_CursorPagination(t *testing.T) {
+	setupTestDB(t)
+	repo := NewSummaryRepository()
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		summary := &domain.Summary{
+			SyncedAt:   now.Add(time.Duration(i) * time.Minute),
+			SourceInfo: domain.ConnectionDetails{Host: "pg1", DBName: fmt.Sprintf("db%d", i)},
+			Schemas:    []domain.Schema{{Name: "public", Tables: make([]domain.Table, i%3)}},
+		}
+		for j := range summary.Schemas[0].Tables {
+			summary.Schemas[0].Tables[j] = domain.Table{Name: fmt.Sprintf("t%d", j), SizeMB: 0.1}
+		}
+		assert.NoError(t, repo.SaveSummary(summary))
+	}
+
+	for _, sort := range []string{domain.SortSyncedAt, domain.SortTotalSize, domain.SortTableCount} {
+		for _, desc := range []bool{false, true} {
+			query := domain.SummaryQuery{Sort: sort, Desc: desc, Compact: true}
+			seen := map[string]bool{}
+			pages := 0
+			for {
+				page, err := repo.GetSummaries(query, 1, 2)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(5), page.Total)
+				for _, item := range page.Items {
+					assert.False(t, seen[item.ID], "%s listed twice sorting by %s", item.ID, sort)
+					seen[item.ID] = true
+					assert.Nil(t, item.Schemas)
+					assert.Equal(t, 1, item.SchemaCount)
+				}
+				pages++
+				if page.NextCursor == "" {
+					break
+				}
+				query.After, err = domain.ParseSummaryCursor(page.NextCursor)
+				assert.NoError(t, err)
+			}
+			assert.Len(t, seen, 5, "sorting by %s", sort)
+			assert.Equal(t, 3, pages)
+		}
+	}
+
+	page, err := repo.GetSummaries(domain.SummaryQuery{Sort: domain.SortTableCount, Desc: true}, 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, page.Items[0].TableCount)
+	assert.InDelta(t, 0.2, page.Items[0].TotalSizeMB, 1e-9)
+	assert.Len(t, page.Items[0].Schemas[0].Tables, 2)
+
+	page, err = repo.GetSummaries(domain.SummaryQuery{Sort: domain.SortTotalSize, Desc: true}, 1, 1)
+	assert.NoError(t, err)
+	cursor, err := domain.ParseSummaryCursor(page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.2", cursor.Value, "the numeric total is carried exactly")
+}
+
+func TestSchemaAndTableSubresources(t *testing.T) {
+	setupTestDB(t)
+	repo := NewSummaryRepository()
+
+	summary := &domain.Summary{
+		SyncedAt:   time.Now(),
+		SourceInfo: domain.ConnectionDetails{Host: "pg1", DBName: "sales"},
+		Schemas: []domain.Schema{
+			{Name: "public", Tables: []domain.Table{
+				{Name: "orders", RowCount: 100, SizeMB: 8, Columns: []domain.Column{{Name: "total", OrdinalPosition: 2}, {Name: "id", OrdinalPosition: 1}}},
+				{Name: "customers", RowCount: 500, SizeMB: 2},
+				{Name: "audit", RowCount: 5, SizeMB: 20},
+			}},
+			{Name: "archive", Tables: []domain.Table{{Name: "orders_2020", SizeMB: 1}}},
+		},
+	}
+	assert.NoError(t, repo.SaveSummary(summary))
+
+	schemas, err := repo.GetSchemas(summary.ID, domain.ListOrder{}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), schemas.Total)
+	assert.Equal(t, "archive", schemas.Items[0].Name)
+	assert.Equal(t, 3, schemas.Items[1].TableCount)
+	assert.InDelta(t, 30, schemas.Items[1].TotalSizeMB, 1e-9)
+
+	schemas, err = repo.GetSchemas(summary.ID, domain.ListOrder{Sort: domain.SortTableCount, Desc: true}, 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), schemas.Total)
+	assert.Len(t, schemas.Items, 1)
+	assert.Equal(t, "public", schemas.Items[0].Name)
+
+	tables, err := repo.GetTables(summary.ID, "public", domain.ListOrder{Sort: domain.SortRowCount, Desc: true}, 2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), tables.Total)
+	assert.Len(t, tables.Items, 1)
+	assert.Equal(t, "audit", tables.Items[0].Name)
+
+	table, err := repo.GetTable(summary.ID, "public", "orders")
+	assert.NoError(t, err)
+	assert.Equal(t, "id", table.Columns[0].Name)
+
+	missing, err := repo.GetSchemas("missing", domain.ListOrder{}, 1, 10)
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+	missingTables, err := repo.GetTables(summary.ID, "nope", domain.ListOrder{}, 1, 10)
+	assert.NoError(t, err)
+	assert.Nil(t, missingTables)
+	missingTable, err := repo.GetTable(summary.ID, "public", "ghost")
+	assert.NoError(t, err)
+	assert.Nil(t, missingTable)
+
+	// Deleted summaries hide their sub-resources too.
+	_, err = repo.DeleteSummary(summary.ID)
+	assert.NoError(t, err)
+	table, err = repo.GetTable(summary.ID, "public", "orders")
+	assert.NoError(t, err)
+	assert.Nil(t, table)
+}
+
+func TestSearch_RanksAcrossDatabases(t *testing.T) {
+	setupTestDB(t)
+	summaries := NewSummaryRepository()
+	search := NewSearchRepository()
+
+	now := time.Now()
+	save := func(db string, syncedAt time.Time, schema string, tables ...string) *domain.Summary {
+		summary := &domain.Summary{SyncedAt: syncedAt, SourceInfo: domain.ConnectionDetails{Host: "pg1", DBName: db},
+			Schemas: []domain.Schema{{Name: schema}}}
+		for _, name := range tables {
+			summary.Schemas[0].Tables = append(summary.Schemas[0].Tables, domain.Table{Name: name, RowCount: 10})
+		}
+		assert.NoError(t, summaries.SaveSummary(summary))
+		return summary
+	}
+	oldBilling := save("billing", now.Add(-time.Hour), "public", "invoices")
+	billing := save("billing", now, "public", "invoices", "invoice_lines")
+	save("sales", now, "invoicing", "orders")
+	save("audit", now, "public", "events")
+
+	page, err := search.Search(domain.SearchQuery{Q: "invoices"}, 1, 10)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, page.Items)
+	first := page.Items[0]
+	assert.Equal(t, domain.MatchTable, first.Kind)
+	assert.Equal(t, "invoices", first.Name)
+	assert.Equal(t, billing.ID, first.SummaryID)
+	assert.Equal(t, "billing", first.DBName)
+	assert.Equal(t, "pg1", first.Host)
+	assert.Equal(t, int64(10), *first.RowCount)
+	for i, match := range page.Items {
+		assert.NotEqual(t, "events", match.Name)
+		assert.NotEqual(t, oldBilling.ID, match.SummaryID, "older snapshots are skipped")
+		if i > 0 {
+			assert.LessOrEqual(t, match.Score, page.Items[i-1].Score+1e-9)
+		}
+	}
+	assert.Equal(t, int64(len(page.Items)), page.Total)
+
+	schemas, err := search.Search(domain.SearchQuery{Q: "invoic", Kind: domain.MatchSchema}, 1, 10)
+	assert.NoError(t, err)
+	assert.Len(t, schemas.Items, 1)
+	assert.Equal(t, "invoicing", schemas.Items[0].Name)
+	assert.Nil(t, schemas.Items[0].RowCount)
+
+	history, err := search.Search(domain.SearchQuery{Q: "invoices", Kind: domain.MatchTable, History: true}, 1, 10)
+	assert.NoError(t, err)
+	found := false
+	for _, match := range history.Items {
+		found = found || match.SummaryID == oldBilling.ID
+	}
+	assert.True(t, found)
+
+	_, err = summaries.DeleteSummary(billing.ID)
+	assert.NoError(t, err)
+	page, err = search.Search(domain.SearchQuery{Q: "invoice_lines", Kind: domain.MatchTable}, 1, 10)
+	assert.NoError(t, err)
+	for _, match := range page.Items {
+		assert.NotEqual(t, billing.ID, match.SummaryID)
+	}
+}
+
+func TestSearch_WithoutTrigram(t *testing.T) {
+	setupTestDB(t)
+	trigramSearch = false
+	t.Cleanup(func() { trigramSearch = true })
+
+	summary := &domain.Summary{SyncedAt: time.Now(), SourceInfo: domain.ConnectionDetails{Host: "pg1", DBName: "billing"},
+		Schemas: []domain.Schema{{Name: "public", Tables: []domain.Table{{Name: "invoices"}, {Name: "invoice_lines"}, {Name: "invocies"}}}}}
+	assert.NoError(t, NewSummaryRepository().SaveSummary(summary))
+
+	page, err := NewSearchRepository().Search(domain.SearchQuery{Q: "invoice", Kind: domain.MatchTable}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), page.Total, "misspellings only match through pg_trgm")
+	assert.Equal(t, "invoice_lines", page.Items[0].Name)
+	assert.Zero(t, page.Items[0].Score)
+}
+
+func TestGetFleetStats_AggregatesLatestSnapshots(t *testing.T) {
+	setupTestDB(t)
+	summaries := NewSummaryRepository()
+	stats := NewStatsRepository()
+
+	now := time.Now()
+	save := func(db string, syncedAt time.Time, tables ...domain.Table) *domain.Summary {
+		summary := &domain.Summary{SyncedAt: syncedAt, SourceInfo: domain.ConnectionDetails{Host: "pg1", DBName: db},
+			Schemas: []domain.Schema{{Name: "public", Tables: tables}, {Name: "empty"}}}
+		assert.NoError(t, summaries.SaveSummary(summary))
+		return summary
+	}
+	save("billing", now.Add(-time.Hour), domain.Table{Name: "invoices", RowCount: 1, SizeMB: 99999})
+	billing := save("billing", now, domain.Table{Name: "invoices", RowCount: 100, SizeMB: 2048}, domain.Table{Name: "payments", RowCount: 50, SizeMB: 10})
+	save("sales", now, domain.Table{Name: "orders", RowCount: 7, SizeMB: 50})
+	audit := save("audit", now, domain.Table{Name: "events", RowCount: 3, SizeMB: 1})
+	_, err := summaries.DeleteSummary(audit.ID)
+	assert.NoError(t, err)
+
+	result, err := stats.GetFleetStats(2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), result.Databases)
+	assert.Equal(t, int64(4), result.Schemas)
+	assert.Equal(t, int64(3), result.Tables)
+	assert.Equal(t, int64(157), result.RowCount)
+	assert.InDelta(t, 2108, result.SizeMB, 1e-6)
+
+	assert.Len(t, result.LargestTables, 2)
+	assert.Equal(t, "invoices", result.LargestTables[0].Name)
+	assert.Equal(t, billing.ID, result.LargestTables[0].SummaryID)
+	assert.Equal(t, "public", result.LargestTables[0].SchemaName)
+	assert.Equal(t, "orders", result.LargestTables[1].Name)
+
+	assert.Len(t, result.LargestDatabases, 2)
+	assert.Equal(t, "billing", result.LargestDatabases[0].DBName)
+	assert.Equal(t, int64(2), result.LargestDatabases[0].TableCount)
+	assert.InDelta(t, 2058, result.LargestDatabases[0].SizeMB, 1e-6)
+
+	assert.Len(t, result.SizeDistribution, 5)
+	assert.Equal(t, int64(1), result.SizeDistribution[0].Databases)
+	assert.Equal(t, int64(0), result.SizeDistribution[1].Databases)
+	assert.Equal(t, int64(1), result.SizeDistribution[2].Databases)
+	assert.Nil(t, result.SizeDistribution[4].MaxMB)
+}
+
+func TestGetTrendPoints(t *testing.T) {
+	setupTestDB(t)
+	summaries := NewSummaryRepository()
+	trends := NewTrendRepository()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	save := func(syncedAt time.Time, tables ...domain.Table) *domain.Summary {
+		summary := &domain.Summary{SyncedAt: syncedAt, SourceInfo: domain.ConnectionDetails{Host: "pg1", DBName: "billing"},
+			Schemas: []domain.Schema{{Name: "public", Tables: tables}, {Name: "empty"}}}
+		assert.NoError(t, summaries.SaveSummary(summary))
+		return summary
+	}
+	old := save(now.AddDate(0, 0, -60), domain.Table{Name: "invoices", RowCount: 1, SizeMB: 1})
+	recent := save(now.AddDate(0, 0, -5), domain.Table{Name: "invoices", RowCount: 10, SizeMB: 10})
+	deleted := save(now.AddDate(0, 0, -1), domain.Table{Name: "invoices", RowCount: 20, SizeMB: 20})
+	_, err := summaries.DeleteSummary(deleted.ID)
+	assert.NoError(t, err)
+
+	points, err := trends.GetTrendPoints(recent.SourceID, now.AddDate(0, 0, -30))
+	assert.NoError(t, err)
+	assert.Len(t, points, 2)
+	assert.Equal(t, recent.ID, points[0].SummaryID)
+	assert.Equal(t, "billing", points[0].DBName)
+	for _, p := range points {
+		if p.SchemaName == "public" {
+			assert.Equal(t, "invoices", p.TableName)
+			assert.Equal(t, int64(10), p.RowCount)
+		} else {
+			assert.Equal(t, "", p.TableName)
+		}
+	}
+
+	// The newest live snapshot is returned even when older than since.
+	points, err = trends.GetTrendPoints(old.SourceID, now)
+	assert.NoError(t, err)
+	assert.Len(t, points, 2)
+	assert.Equal(t, recent.ID, points[0].SummaryID)
+
+	points, err = trends.GetTrendPoints("missing", now)
+	assert.NoError(t, err)
+	assert.Empty(t, points)
+}
@@ -0,0 +1,54 @@
+package local
+
+import (
+	"time"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+)
+
+type JobRepository interface {
+	CreateJob(job *domain.SyncJob) error
+	UpdateJob(job *domain.SyncJob) error
+	GetJobByID(id string) (*domain.SyncJob, error)
+	FailUnfinishedJobs(reason string) (int64, error)
+}
+
+type jobRepo struct{}
+
+func NewJobRepository() JobRepository {
+	return &jobRepo{}
+}
+
+func (r *jobRepo) CreateJob(job *domain.SyncJob) error {
+	return dB.Create(job).Error
+}
+
+func (r *jobRepo) UpdateJob(job *domain.SyncJob) error {
+	return dB.Save(job).Error
+}
+
+// GetJobByID returns the job with the given ID, or nil when it does not exist.
+func (r *jobRepo) GetJobByID(id string) (*domain.SyncJob, error) {
+	var job domain.SyncJob
+	if err := dB.First(&job, "id = ?", id).Error; err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// FailUnfinishedJobs marks jobs left queued or running by a previous process
+// as failed. The in-memory queue does not survive restarts, so they would
+// otherwise stay pending forever.
+func (r *jobRepo) FailUnfinishedJobs(reason string) (int64, error) {
+	result := dB.Model(&domain.SyncJob{}).
+		Where("status IN ?", []string{domain.JobQueued, domain.JobRunning}).
+		Updates(map[string]interface{}{
+			"status":      domain.JobFailed,
+			"error":       reason,
+			"finished_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
@@ -2,10 +2,8 @@ package local
 
 import (
 	"log"
-	"os"
-	"time"
 
-	"github.com/joho/godotenv"
+	"github.com/lokesh2201013/postgres-data-summary/internal/config"
 	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -13,19 +11,9 @@ import (
 
 var dB *gorm.DB
 
-func ConnectDB() {
-	if err := godotenv.Load(); err != nil {
-		log.Println("Warning: No .env file found")
-	}
-
-	dsn := "host=" + os.Getenv("DB_HOST") +
-		" user=" + os.Getenv("DB_USER") +
-		" password=" + os.Getenv("DB_PASSWORD") +
-		" dbname=" + os.Getenv("DB_NAME") +
-		" port=" + os.Getenv("DB_PORT") +
-		" sslmode=disable"
-
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+// ConnectDB opens the connection pool described by cfg and runs migrations.
+func ConnectDB(cfg config.DatabaseConfig) {
+	db, err := gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{})
 	if err != nil {
 		log.Fatalf("Failed to connect to db: %v\n", err)
 	}
@@ -35,20 +23,27 @@ func ConnectDB() {
 		log.Fatalf("Failed to get sql.DB from GORM: %v\n", err)
 	}
 
-	sqlDB.SetMaxOpenConns(5)          
-	sqlDB.SetMaxIdleConns(3)           
-	sqlDB.SetConnMaxLifetime(30 * time.Minute) 
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
 	// Run migrations
-	if err := db.AutoMigrate(&domain.ConnectionDetails{}, &domain.Table{}); err != nil {
+	if err := db.AutoMigrate(&domain.ConnectionDetails{}, &domain.Table{}, &domain.Column{}, &domain.Index{}, &domain.Constraint{}); err != nil {
 		log.Fatalf("AutoMigrate failed: %v\n", err)
 	}
 	if err := db.AutoMigrate( &domain.Schema{}); err != nil {
 		log.Fatalf("AutoMigrate failed: %v\n", err)
 	}
-	if err := db.AutoMigrate(&domain.Summary{}); err != nil {
+	if err := db.AutoMigrate(&domain.Server{}, &domain.Source{}, &domain.Summary{}, &domain.SyncJob{}, &domain.Schedule{}); err != nil {
 		log.Fatalf("AutoMigrate failed: %v\n", err)
 	}
+	if err := dropLegacySummaryPasswords(db); err != nil {
+		log.Fatalf("Dropping legacy password column failed: %v\n", err)
+	}
+	if err := ensureSearchIndexes(db); err != nil {
+		trigramSearch = false
+		log.Printf("WARNING: pg_trgm is unavailable, search falls back to substring matches without an index: %v\n", err)
+	}
 
 	dB = db
 	log.Println("Connected to PostgreSQL using GORM with connection pooling")
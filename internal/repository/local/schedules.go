@@ -0,0 +1,120 @@
+package local
+
+import (
+	"time"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+)
+
+type ScheduleRepository interface {
+	CreateSchedule(schedule *domain.Schedule) error
+	UpdateSchedule(schedule *domain.Schedule) error
+	GetSchedules() ([]domain.Schedule, error)
+	GetScheduleByID(id string) (*domain.Schedule, error)
+	GetDueSchedules(now time.Time) ([]domain.Schedule, error)
+	RecordScheduleRun(id string, ranAt time.Time, jobID string, next time.Time) error
+	RecordScheduleError(id, message string, next time.Time, pause bool) error
+	DeleteSchedule(id string) (bool, error)
+	GetSchedulesWithPassword() ([]domain.Schedule, error)
+	UpdateSchedulePassword(id, ciphertext string) error
+}
+
+type scheduleRepo struct{}
+
+func NewScheduleRepository() ScheduleRepository {
+	return &scheduleRepo{}
+}
+
+func (r *scheduleRepo) CreateSchedule(schedule *domain.Schedule) error {
+	return dB.Create(schedule).Error
+}
+
+// UpdateSchedule writes the pause state, next run and last error of a
+// schedule, leaving columns written concurrently by the scheduler or a
+// password rewrap untouched.
+func (r *scheduleRepo) UpdateSchedule(schedule *domain.Schedule) error {
+	return dB.Model(&domain.Schedule{ID: schedule.ID}).
+		Select("paused", "next_run_at", "last_error").
+		Updates(schedule).Error
+}
+
+func (r *scheduleRepo) GetSchedules() ([]domain.Schedule, error) {
+	var schedules []domain.Schedule
+	if err := dB.Order("created_at").Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// GetScheduleByID returns the schedule with the given ID, or nil when it
+// does not exist.
+func (r *scheduleRepo) GetScheduleByID(id string) (*domain.Schedule, error) {
+	var schedule domain.Schedule
+	if err := dB.First(&schedule, "id = ?", id).Error; err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// GetDueSchedules returns active schedules whose next run is at or before now.
+func (r *scheduleRepo) GetDueSchedules(now time.Time) ([]domain.Schedule, error) {
+	var schedules []domain.Schedule
+	err := dB.
+		Where("paused = ? AND next_run_at <= ?", false, now).
+		Order("next_run_at").
+		Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// RecordScheduleRun stores the outcome of a scheduler tick. Only the run
+// columns are written so a concurrent pause is not overwritten.
+func (r *scheduleRepo) RecordScheduleRun(id string, ranAt time.Time, jobID string, next time.Time) error {
+	return dB.Model(&domain.Schedule{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"last_run_at": ranAt,
+			"last_job_id": jobID,
+			"next_run_at": next,
+			"last_error":  "",
+		}).Error
+}
+
+// RecordScheduleError stores why a due run could not be queued, moves the
+// schedule to next and pauses it when pause is set.
+func (r *scheduleRepo) RecordScheduleError(id, message string, next time.Time, pause bool) error {
+	updates := map[string]interface{}{
+		"last_error":  message,
+		"next_run_at": next,
+	}
+	if pause {
+		updates["paused"] = true
+	}
+	return dB.Model(&domain.Schedule{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// DeleteSchedule removes a schedule, reporting whether it existed.
+func (r *scheduleRepo) DeleteSchedule(id string) (bool, error) {
+	result := dB.Delete(&domain.Schedule{}, "id = ?", id)
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetSchedulesWithPassword returns every schedule that stores an encrypted
+// target password.
+func (r *scheduleRepo) GetSchedulesWithPassword() ([]domain.Schedule, error) {
+	var schedules []domain.Schedule
+	if err := dB.Where("target_password_ciphertext <> ''").Order("id").Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// UpdateSchedulePassword replaces only the stored target password ciphertext.
+func (r *scheduleRepo) UpdateSchedulePassword(id, ciphertext string) error {
+	return dB.Model(&domain.Schedule{}).Where("id = ?", id).Update("target_password_ciphertext", ciphertext).Error
+}
@@ -0,0 +1,142 @@
+package local
+
+import (
+	"fmt"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"gorm.io/gorm"
+)
+
+// schemaOrders and tableOrders map ListOrder.Sort keys to ORDER BY
+// expressions of the sub-resource lists.
+var (
+	schemaOrders = map[string]string{
+		"":                    "schemas.name",
+		domain.SortName:       "schemas.name",
+		domain.SortTableCount: "table_count",
+		domain.SortTotalSize:  "total_size_mb",
+	}
+	tableOrders = map[string]string{
+		"":                  "tables.name",
+		domain.SortName:     "tables.name",
+		domain.SortRowCount: "tables.row_count",
+		domain.SortSizeMB:   "tables.size_mb",
+	}
+)
+
+// orderBy returns the ORDER BY clause for order, breaking ties by id so
+// pages are stable.
+func orderBy(orders map[string]string, order domain.ListOrder, id string) (string, error) {
+	expr, ok := orders[order.Sort]
+	if !ok {
+		return "", fmt.Errorf("unknown sort %q", order.Sort)
+	}
+	if order.Desc {
+		return expr + " DESC, " + id + " DESC", nil
+	}
+	return expr + " ASC, " + id + " ASC", nil
+}
+
+// summaryExists reports whether a summary that is not deleted has the ID.
+func summaryExists(id string) (bool, error) {
+	var count int64
+	err := dB.Model(&domain.Summary{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+// findSchema returns the schema named name of a live summary, or nil when
+// either does not exist.
+func findSchema(summaryID, name string) (*domain.Schema, error) {
+	exists, err := summaryExists(summaryID)
+	if err != nil || !exists {
+		return nil, err
+	}
+	var schema domain.Schema
+	if err := dB.First(&schema, "summary_id = ? AND name = ?", summaryID, name).Error; err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// GetSchemas lists a page of the schemas of a summary with their table count
+// and total size, or returns nil when the summary does not exist.
+func (r *summaryRepo) GetSchemas(summaryID string, order domain.ListOrder, page, pageSize int) (*domain.SchemaPage, error) {
+	orderClause, err := orderBy(schemaOrders, order, "schemas.id")
+	if err != nil {
+		return nil, err
+	}
+	exists, err := summaryExists(summaryID)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	result := &domain.SchemaPage{Items: []domain.SchemaListItem{}}
+	if err := dB.Model(&domain.Schema{}).Where("summary_id = ?", summaryID).Count(&result.Total).Error; err != nil {
+		return nil, err
+	}
+	err = dB.Table("schemas").
+		Select("schemas.id, schemas.summary_id, schemas.name, "+
+			"(SELECT COUNT(*) FROM tables WHERE tables.schema_id = schemas.id) AS table_count, "+
+			"(SELECT COALESCE(SUM(tables.size_mb::numeric), 0) FROM tables WHERE tables.schema_id = schemas.id) AS total_size_mb").
+		Where("schemas.summary_id = ?", summaryID).
+		Order(orderClause).
+		Limit(pageSize).
+		Offset((page - 1) * pageSize).
+		Scan(&result.Items).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetTables lists a page of the tables of a schema without their children,
+// or returns nil when the summary or schema does not exist.
+func (r *summaryRepo) GetTables(summaryID, schemaName string, order domain.ListOrder, page, pageSize int) (*domain.TablePage, error) {
+	orderClause, err := orderBy(tableOrders, order, "tables.id")
+	if err != nil {
+		return nil, err
+	}
+	schema, err := findSchema(summaryID, schemaName)
+	if err != nil || schema == nil {
+		return nil, err
+	}
+
+	result := &domain.TablePage{Items: []domain.TableListItem{}}
+	tables := func() *gorm.DB {
+		return dB.Model(&domain.Table{}).Where("tables.schema_id = ?", schema.ID)
+	}
+	if err := tables().Count(&result.Total).Error; err != nil {
+		return nil, err
+	}
+	err = tables().
+		Select("tables.id, tables.schema_id, tables.name, tables.row_count, tables.size_mb").
+		Order(orderClause).
+		Limit(pageSize).
+		Offset((page - 1) * pageSize).
+		Scan(&result.Items).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetTable returns a table of a schema with its columns, indexes and
+// constraints, or nil when the summary, schema or table does not exist.
+func (r *summaryRepo) GetTable(summaryID, schemaName, tableName string) (*domain.Table, error) {
+	schema, err := findSchema(summaryID, schemaName)
+	if err != nil || schema == nil {
+		return nil, err
+	}
+	var table domain.Table
+	err = preloadTableChildren(dB, "").First(&table, "schema_id = ? AND name = ?", schema.ID, tableName).Error
+	if err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &table, nil
+}
@@ -0,0 +1,110 @@
+package local
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+)
+
+type SearchRepository interface {
+	Search(query domain.SearchQuery, page, pageSize int) (*domain.SearchPage, error)
+}
+
+type searchRepo struct{}
+
+func NewSearchRepository() SearchRepository {
+	return &searchRepo{}
+}
+
+// trigramSearch reports whether pg_trgm is installed. Without it Search
+// falls back to substring matches and scores every match 0.
+var trigramSearch = true
+
+// ensureSearchIndexes installs pg_trgm and the trigram indexes that serve
+// both the similarity operator and ILIKE on table and schema names. pg_trgm
+// is a trusted extension, so the database owner can create it.
+func ensureSearchIndexes(db *gorm.DB) error {
+	for _, stmt := range []string{
+		"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+		"CREATE INDEX IF NOT EXISTS idx_tables_name_trgm ON tables USING gin (name gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_schemas_name_trgm ON schemas USING gin (name gin_trgm_ops)",
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trigramMatches selects every table and schema whose name is similar to the
+// query (the pg_trgm % operator) or contains it.
+const trigramMatches = `
+	SELECT 'table' AS kind, tables.id, tables.name, schemas.name AS schema_name, schemas.summary_id,
+		tables.row_count, tables.size_mb, similarity(tables.name, @q) AS score
+	FROM tables JOIN schemas ON schemas.id = tables.schema_id
+	WHERE tables.name % @q OR tables.name ILIKE @pattern
+	UNION ALL
+	SELECT 'schema', schemas.id, schemas.name, schemas.name, schemas.summary_id,
+		NULL, NULL, similarity(schemas.name, @q)
+	FROM schemas
+	WHERE schemas.name % @q OR schemas.name ILIKE @pattern`
+
+// substringMatches selects every table and schema whose name contains the
+// query, for databases without pg_trgm.
+const substringMatches = `
+	SELECT 'table' AS kind, tables.id, tables.name, schemas.name AS schema_name, schemas.summary_id,
+		tables.row_count, tables.size_mb, 0::real AS score
+	FROM tables JOIN schemas ON schemas.id = tables.schema_id
+	WHERE tables.name ILIKE @pattern
+	UNION ALL
+	SELECT 'schema', schemas.id, schemas.name, schemas.name, schemas.summary_id,
+		NULL, NULL, 0::real
+	FROM schemas
+	WHERE schemas.name ILIKE @pattern`
+
+// Search ranks exact name matches first, then by similarity, and skips
+// deleted summaries. Without History only the newest snapshot of each
+// source is searched.
+func (r *searchRepo) Search(query domain.SearchQuery, page, pageSize int) (*domain.SearchPage, error) {
+	args := map[string]interface{}{
+		"q":       query.Q,
+		"lower":   strings.ToLower(query.Q),
+		"pattern": "%" + escapeLike(query.Q) + "%",
+		"kind":    query.Kind,
+		"limit":   pageSize,
+		"offset":  (page - 1) * pageSize,
+	}
+
+	var where strings.Builder
+	where.WriteString("summaries.deleted_at IS NULL")
+	if query.Kind != "" {
+		where.WriteString(" AND matches.kind = @kind")
+	}
+	if !query.History {
+		where.WriteString(` AND summaries.synced_at = (
+			SELECT MAX(latest.synced_at) FROM summaries latest
+			WHERE latest.source_id = summaries.source_id AND latest.deleted_at IS NULL)`)
+	}
+	matches := trigramMatches
+	if !trigramSearch {
+		matches = substringMatches
+	}
+	from := "FROM (" + matches + ") matches JOIN summaries ON summaries.id = matches.summary_id WHERE " + where.String()
+
+	result := &domain.SearchPage{Items: []domain.SearchMatch{}}
+	if err := dB.Raw("SELECT COUNT(*) "+from, args).Scan(&result.Total).Error; err != nil {
+		return nil, err
+	}
+	err := dB.Raw(`SELECT matches.*, summaries.source_id, summaries.source_host AS host,
+			summaries.source_db_name AS db_name, summaries.synced_at `+from+`
+		ORDER BY lower(matches.name) = @lower DESC, matches.score DESC, matches.name,
+			summaries.synced_at DESC, matches.id
+		LIMIT @limit OFFSET @offset`, args).
+		Scan(&result.Items).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
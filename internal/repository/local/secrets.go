@@ -0,0 +1,22 @@
+package local
+
+import (
+	"log"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"gorm.io/gorm"
+)
+
+// legacySummaryPasswordColumn held the plaintext password of the embedded
+// ConnectionDetails before it was excluded from the summaries table.
+const legacySummaryPasswordColumn = "source_password"
+
+// dropLegacySummaryPasswords removes summaries.source_password. Summaries
+// were always saved with the password blanked, so there is nothing to keep.
+func dropLegacySummaryPasswords(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&domain.Summary{}, legacySummaryPasswordColumn) {
+		return nil
+	}
+	log.Println("Dropping legacy plaintext column summaries." + legacySummaryPasswordColumn)
+	return db.Migrator().DropColumn(&domain.Summary{}, legacySummaryPasswordColumn)
+}
@@ -0,0 +1,94 @@
+package local
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+)
+
+type ServerRepository interface {
+	FindOrCreateServer(host string, port int) (*domain.Server, error)
+	GetServers(page, pageSize int) ([]domain.Server, error)
+	GetServerByID(id string) (*domain.Server, error)
+	RecordDiscovery(id string, discoveredAt time.Time, user string, dbNames []string) error
+}
+
+type serverRepo struct{}
+
+func NewServerRepository() ServerRepository {
+	return &serverRepo{}
+}
+
+// FindOrCreateServer returns the Server at host and port, creating it on
+// first discovery.
+func (r *serverRepo) FindOrCreateServer(host string, port int) (*domain.Server, error) {
+	candidate := domain.Server{ID: uuid.NewString(), Host: host, Port: port, CreatedAt: time.Now()}
+	if err := dB.Clauses(clause.OnConflict{DoNothing: true}).Create(&candidate).Error; err != nil {
+		return nil, err
+	}
+
+	var server domain.Server
+	if err := dB.First(&server, "host = ? AND port = ?", host, port).Error; err != nil {
+		return nil, err
+	}
+	return &server, nil
+}
+
+func (r *serverRepo) GetServers(page, pageSize int) ([]domain.Server, error) {
+	var servers []domain.Server
+	err := dB.
+		Order("host, port").
+		Limit(pageSize).
+		Offset((page - 1) * pageSize).
+		Find(&servers).Error
+	if err != nil {
+		return nil, err
+	}
+	return servers, nil
+}
+
+// GetServerByID returns the server with its sources, or nil when it does not
+// exist.
+func (r *serverRepo) GetServerByID(id string) (*domain.Server, error) {
+	var server domain.Server
+	err := dB.Preload("Sources", func(db *gorm.DB) *gorm.DB { return db.Order("db_name") }).
+		First(&server, "id = ?", id).Error
+	if err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &server, nil
+}
+
+// RecordDiscovery stamps the server as discovered and groups the sources of
+// dbNames on its host and port under it, creating the sources of databases
+// not synced yet so their first sync lands in the group.
+func (r *serverRepo) RecordDiscovery(id string, discoveredAt time.Time, user string, dbNames []string) error {
+	return dB.Transaction(func(tx *gorm.DB) error {
+		var server domain.Server
+		if err := tx.First(&server, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&server).Update("last_discovered_at", discoveredAt).Error; err != nil {
+			return err
+		}
+		if len(dbNames) == 0 {
+			return nil
+		}
+		for _, name := range dbNames {
+			details := domain.ConnectionDetails{Host: server.Host, Port: &server.Port, DBName: name, User: user}
+			if _, err := findOrCreateSource(tx, details); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&domain.Source{}).
+			Where("host = ? AND port = ? AND db_name IN ?", server.Host, server.Port, dbNames).
+			Update("server_id", id).Error
+	})
+}
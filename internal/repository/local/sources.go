@@ -0,0 +1,134 @@
+package local
+
+import (
+	"github.com/google/uuid"
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SourceRepository interface {
+	CreateSource(source *domain.Source) error
+	UpdateSource(source *domain.Source, withPassword bool) error
+	GetSources(page, pageSize int) ([]domain.Source, error)
+	GetSourceByID(id string) (*domain.Source, error)
+	GetSourceByTarget(host string, port int, dbName string) (*domain.Source, error)
+	DeleteSource(id string) (bool, error)
+	GetSourcesWithPassword() ([]domain.Source, error)
+	UpdateSourcePassword(id, ciphertext string) error
+}
+
+type sourceRepo struct{}
+
+func NewSourceRepository() SourceRepository {
+	return &sourceRepo{}
+}
+
+func (r *sourceRepo) CreateSource(source *domain.Source) error {
+	return dB.Create(source).Error
+}
+
+// UpdateSource writes the connection settings of a source, and its password
+// only when withPassword is set, so a concurrent password rewrap is not
+// overwritten with a stale ciphertext.
+func (r *sourceRepo) UpdateSource(source *domain.Source, withPassword bool) error {
+	columns := []string{"host", "port", "db_name", "user", "ssl_mode", "registered", "updated_at"}
+	if withPassword {
+		columns = append(columns, "password_ciphertext", "has_password")
+	}
+	return dB.Model(&domain.Source{ID: source.ID}).Select(columns).Updates(source).Error
+}
+
+func (r *sourceRepo) GetSources(page, pageSize int) ([]domain.Source, error) {
+	var sources []domain.Source
+	err := dB.
+		Order("host, port, db_name").
+		Limit(pageSize).
+		Offset((page - 1) * pageSize).
+		Find(&sources).Error
+	if err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
+
+// GetSourceByID returns the source with the given ID, or nil when it does
+// not exist.
+func (r *sourceRepo) GetSourceByID(id string) (*domain.Source, error) {
+	return firstSource(dB.Where("id = ?", id))
+}
+
+// GetSourceByTarget returns the source for a host, port and database, or nil
+// when none has been registered or synced yet.
+func (r *sourceRepo) GetSourceByTarget(host string, port int, dbName string) (*domain.Source, error) {
+	return firstSource(dB.Where("host = ? AND port = ? AND db_name = ?", host, port, dbName))
+}
+
+// DeleteSource removes a source and its stored credentials, reporting whether
+// it existed. Snapshots already taken keep their source_id.
+func (r *sourceRepo) DeleteSource(id string) (bool, error) {
+	result := dB.Delete(&domain.Source{}, "id = ?", id)
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetSourcesWithPassword returns every source that stores an encrypted
+// password.
+func (r *sourceRepo) GetSourcesWithPassword() ([]domain.Source, error) {
+	var sources []domain.Source
+	if err := dB.Where("has_password = ?", true).Order("id").Find(&sources).Error; err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
+
+// UpdateSourcePassword replaces only the stored ciphertext of a source.
+func (r *sourceRepo) UpdateSourcePassword(id, ciphertext string) error {
+	return dB.Model(&domain.Source{}).Where("id = ?", id).Update("password_ciphertext", ciphertext).Error
+}
+
+func firstSource(query *gorm.DB) (*domain.Source, error) {
+	var source domain.Source
+	if err := query.First(&source).Error; err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &source, nil
+}
+
+// findOrCreateSource returns the Source matching the host, port and database
+// of details, creating it on first sight with domain.SourceID. Concurrent
+// first syncs of the same database converge on a single row through the
+// unique target index.
+func findOrCreateSource(tx *gorm.DB, details domain.ConnectionDetails) (*domain.Source, error) {
+	candidate := domain.Source{
+		ID:     domain.SourceID(details.Host, details.PortOrDefault(), details.DBName),
+		Host:   details.Host,
+		Port:   details.PortOrDefault(),
+		DBName: details.DBName,
+		User:   details.User,
+	}
+	source, err := createSourceIfMissing(tx, candidate)
+	if err == nil && source == nil {
+		// The derived ID belongs to a saved source whose target was edited
+		// since; fall back to a random ID for this target.
+		candidate.ID = uuid.NewString()
+		source, err = createSourceIfMissing(tx, candidate)
+	}
+	if err == nil && source == nil {
+		err = gorm.ErrRecordNotFound
+	}
+	return source, err
+}
+
+// createSourceIfMissing inserts candidate unless its ID or target is taken,
+// then returns the source stored for its target, or nil when the ID was
+// taken by another target.
+func createSourceIfMissing(tx *gorm.DB, candidate domain.Source) (*domain.Source, error) {
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&candidate).Error; err != nil {
+		return nil, err
+	}
+	return firstSource(tx.Where("host = ? AND port = ? AND db_name = ?",
+		candidate.Host, candidate.Port, candidate.DBName))
+}
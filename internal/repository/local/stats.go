@@ -0,0 +1,133 @@
+package local
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+)
+
+type StatsRepository interface {
+	GetFleetStats(top int) (*domain.FleetStats, error)
+}
+
+type statsRepo struct{}
+
+func NewStatsRepository() StatsRepository {
+	return &statsRepo{}
+}
+
+// sizeBuckets are the upper bounds, in MB, of the database size
+// distribution; the last bucket is open-ended.
+var sizeBuckets = []struct {
+	label string
+	maxMB float64
+}{
+	{"< 100 MB", 100},
+	{"100 MB - 1 GB", 1024},
+	{"1 GB - 10 GB", 10 * 1024},
+	{"10 GB - 100 GB", 100 * 1024},
+	{">= 100 GB", 0},
+}
+
+// latestDatabases is a CTE totalling the newest live snapshot of every
+// source, one row per source.
+const latestDatabases = `
+	WITH latest AS (
+		SELECT DISTINCT ON (source_id) id, source_id, source_host, source_db_name, synced_at
+		FROM summaries
+		WHERE deleted_at IS NULL
+		ORDER BY source_id, synced_at DESC, id DESC
+	), databases AS (
+		SELECT latest.id AS summary_id, latest.source_id, latest.source_host AS host,
+			latest.source_db_name AS db_name, latest.synced_at,
+			COUNT(DISTINCT schemas.id) AS schema_count, COUNT(tables.id) AS table_count,
+			COALESCE(SUM(tables.row_count), 0) AS row_count,
+			COALESCE(SUM(tables.size_mb::numeric), 0) AS size_mb
+		FROM latest
+		LEFT JOIN schemas ON schemas.summary_id = latest.id
+		LEFT JOIN tables ON tables.schema_id = schemas.id
+		GROUP BY latest.id, latest.source_id, latest.source_host, latest.source_db_name, latest.synced_at
+	)`
+
+// GetFleetStats aggregates the newest snapshot of every source in SQL,
+// listing the top largest tables and databases.
+func (r *statsRepo) GetFleetStats(top int) (*domain.FleetStats, error) {
+	var totals struct {
+		Databases int64
+		Schemas   int64
+		Tables    int64
+		RowCount  int64
+		SizeMB    float64
+	}
+	err := dB.Raw(latestDatabases + `
+		SELECT COUNT(*) AS databases, COALESCE(SUM(schema_count), 0) AS schemas,
+			COALESCE(SUM(table_count), 0) AS tables, COALESCE(SUM(row_count), 0) AS row_count,
+			COALESCE(SUM(size_mb), 0) AS size_mb
+		FROM databases`).Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	stats := &domain.FleetStats{
+		Databases:        totals.Databases,
+		Schemas:          totals.Schemas,
+		Tables:           totals.Tables,
+		RowCount:         totals.RowCount,
+		SizeMB:           totals.SizeMB,
+		LargestTables:    []domain.TableStat{},
+		LargestDatabases: []domain.DatabaseStat{},
+	}
+
+	err = dB.Raw(latestDatabases+`
+		SELECT databases.summary_id, databases.host, databases.db_name, schemas.name AS schema_name,
+			tables.name, tables.row_count, tables.size_mb
+		FROM databases
+		JOIN schemas ON schemas.summary_id = databases.summary_id
+		JOIN tables ON tables.schema_id = schemas.id
+		ORDER BY tables.size_mb DESC, tables.id
+		LIMIT ?`, top).Scan(&stats.LargestTables).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = dB.Raw(latestDatabases+`
+		SELECT summary_id, source_id, host, db_name, synced_at, table_count, row_count, size_mb
+		FROM databases
+		ORDER BY size_mb DESC, summary_id
+		LIMIT ?`, top).Scan(&stats.LargestDatabases).Error
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := make([]string, 0, len(sizeBuckets)-1)
+	for _, bucket := range sizeBuckets[:len(sizeBuckets)-1] {
+		bounds = append(bounds, fmt.Sprint(bucket.maxMB))
+	}
+	var counts []struct {
+		Bucket    int
+		Databases int64
+	}
+	err = dB.Raw(latestDatabases + `
+		SELECT width_bucket(size_mb::float8, ARRAY[` + strings.Join(bounds, ", ") + `]::float8[]) AS bucket,
+			COUNT(*) AS databases
+		FROM databases
+		GROUP BY bucket`).Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	minMB := 0.0
+	for _, bucket := range sizeBuckets {
+		entry := domain.SizeBucket{Label: bucket.label, MinMB: minMB}
+		if bucket.maxMB > 0 {
+			maxMB := bucket.maxMB
+			entry.MaxMB = &maxMB
+			minMB = maxMB
+		}
+		stats.SizeDistribution = append(stats.SizeDistribution, entry)
+	}
+	for _, count := range counts {
+		stats.SizeDistribution[count.Bucket].Databases = count.Databases
+	}
+	return stats, nil
+}
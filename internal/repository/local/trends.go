@@ -0,0 +1,49 @@
+package local
+
+import (
+	"time"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+)
+
+type TrendRepository interface {
+	GetTrendPoints(sourceID string, since time.Time) ([]domain.TrendPoint, error)
+}
+
+type trendRepo struct{}
+
+func NewTrendRepository() TrendRepository {
+	return &trendRepo{}
+}
+
+// GetTrendPoints returns the tables of every live snapshot of a source
+// synced at or after since, plus the newest snapshot however old it is,
+// oldest first. It returns no points when the source has no live
+// snapshots.
+func (r *trendRepo) GetTrendPoints(sourceID string, since time.Time) ([]domain.TrendPoint, error) {
+	var points []domain.TrendPoint
+	err := dB.Raw(`
+		WITH snapshots AS (
+			SELECT id, synced_at, source_host, source_db_name
+			FROM summaries
+			WHERE source_id = @source AND deleted_at IS NULL
+				AND (synced_at >= @since OR id = (
+					SELECT id FROM summaries
+					WHERE source_id = @source AND deleted_at IS NULL
+					ORDER BY synced_at DESC, id DESC
+					LIMIT 1))
+		)
+		SELECT snapshots.id AS summary_id, snapshots.synced_at,
+			snapshots.source_host AS host, snapshots.source_db_name AS db_name,
+			COALESCE(schemas.name, '') AS schema_name, COALESCE(tables.name, '') AS table_name,
+			COALESCE(tables.row_count, 0) AS row_count, COALESCE(tables.size_mb, 0) AS size_mb
+		FROM snapshots
+		LEFT JOIN schemas ON schemas.summary_id = snapshots.id
+		LEFT JOIN tables ON tables.schema_id = schemas.id
+		ORDER BY snapshots.synced_at, snapshots.id`,
+		map[string]interface{}{"source": sourceID, "since": since}).Scan(&points).Error
+	if err != nil {
+		return nil, err
+	}
+	return points, nil
+}
@@ -0,0 +1,88 @@
+// Package retry runs operations under a retry policy with exponential
+// backoff, jitter and a bound on the total time spent.
+package retry
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// DefaultMultiplier is used when Policy.Multiplier is not set.
+const DefaultMultiplier = 2
+
+// Policy describes how a failing operation is retried. The delay before
+// attempt n+1 is InitialDelay*Multiplier^(n-1), randomized by ±Jitter and
+// capped at MaxDelay.
+type Policy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values below 1 mean a single attempt.
+	MaxAttempts  int
+	InitialDelay time.Duration
+	// MaxDelay caps a single delay; zero means no cap.
+	MaxDelay time.Duration
+	// Multiplier grows the delay after each attempt; zero means
+	// DefaultMultiplier.
+	Multiplier float64
+	// Jitter is the fraction, between 0 and 1, by which each delay is
+	// randomly shortened or lengthened so that callers failing together do
+	// not retry together.
+	Jitter float64
+	// MaxElapsed stops retrying once the next attempt would start later than
+	// this long after the first one; zero means no limit.
+	MaxElapsed time.Duration
+}
+
+// Classifier reports whether err is worth retrying.
+type Classifier func(err error) bool
+
+// random returns a number in [0, 1); tests replace it.
+var random = rand.Float64
+
+// Delay returns the wait before the attempt following attempt, which
+// counts from 1.
+func (p Policy) Delay(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = DefaultMultiplier
+	}
+	delay := float64(p.InitialDelay) * math.Pow(multiplier, float64(attempt-1))
+	if p.Jitter > 0 {
+		delay *= 1 + p.Jitter*(2*random()-1)
+	}
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	return time.Duration(delay)
+}
+
+// Do calls op until it succeeds, returns an error retryable rejects, the
+// attempts or elapsed time run out, or ctx is done. op receives the attempt
+// number starting at 1. A nil retryable retries every error. Do returns the
+// last error from op, or ctx's error when it ended the wait between
+// attempts.
+func (p Policy) Do(ctx context.Context, retryable Classifier, op func(attempt int) error) error {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		err := op(attempt)
+		if err == nil || ctx.Err() != nil || attempt >= p.MaxAttempts {
+			return err
+		}
+		if retryable != nil && !retryable(err) {
+			return err
+		}
+
+		delay := p.Delay(attempt)
+		if p.MaxElapsed > 0 && time.Since(start)+delay > p.MaxElapsed {
+			return err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
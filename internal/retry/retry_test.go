@@ -0,0 +1,99 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errTransient = errors.New("transient")
+
+func TestDelay_Backoff(t *testing.T) {
+	p := Policy{InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	assert.Equal(t, 100*time.Millisecond, p.Delay(1))
+	assert.Equal(t, 200*time.Millisecond, p.Delay(2))
+	assert.Equal(t, 400*time.Millisecond, p.Delay(3))
+	assert.Equal(t, time.Second, p.Delay(10))
+
+	p.Multiplier = 3
+	assert.Equal(t, 900*time.Millisecond, p.Delay(3))
+}
+
+func TestDelay_Jitter(t *testing.T) {
+	defer func(orig func() float64) { random = orig }(random)
+	p := Policy{InitialDelay: 100 * time.Millisecond, Jitter: 0.5}
+
+	random = func() float64 { return 0 }
+	assert.Equal(t, 50*time.Millisecond, p.Delay(1))
+	random = func() float64 { return 0.5 }
+	assert.Equal(t, 100*time.Millisecond, p.Delay(1))
+	random = func() float64 { return 0.999999 }
+	assert.InDelta(t, float64(150*time.Millisecond), float64(p.Delay(1)), float64(time.Microsecond))
+}
+
+func TestDo_RetriesUntilSuccess(t *testing.T) {
+	calls := 0
+	err := Policy{MaxAttempts: 3}.Do(context.Background(), nil, func(attempt int) error {
+		calls++
+		assert.Equal(t, calls, attempt)
+		if attempt < 3 {
+			return errTransient
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestDo_StopsAfterMaxAttempts(t *testing.T) {
+	calls := 0
+	start := time.Now()
+	err := Policy{MaxAttempts: 2, InitialDelay: 20 * time.Millisecond}.Do(context.Background(), nil, func(int) error {
+		calls++
+		return errTransient
+	})
+	assert.ErrorIs(t, err, errTransient)
+	assert.Equal(t, 2, calls)
+	// Only the wait between the two attempts; none after the last one.
+	assert.Less(t, time.Since(start), 40*time.Millisecond)
+}
+
+func TestDo_DoesNotRetryPermanentErrors(t *testing.T) {
+	permanent := errors.New("bad password")
+	calls := 0
+	err := Policy{MaxAttempts: 5}.Do(context.Background(), func(err error) bool { return err != permanent }, func(int) error {
+		calls++
+		return permanent
+	})
+	assert.ErrorIs(t, err, permanent)
+	assert.Equal(t, 1, calls)
+}
+
+func TestDo_MaxElapsed(t *testing.T) {
+	calls := 0
+	p := Policy{MaxAttempts: 10, InitialDelay: 30 * time.Millisecond, MaxElapsed: 50 * time.Millisecond}
+	err := p.Do(context.Background(), nil, func(int) error {
+		calls++
+		return errTransient
+	})
+	assert.ErrorIs(t, err, errTransient)
+	// 30ms fits in the budget, the following 60ms does not.
+	assert.Equal(t, 2, calls)
+}
+
+func TestDo_CancelledDuringWait(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	err := Policy{MaxAttempts: 3, InitialDelay: time.Minute}.Do(ctx, nil, func(int) error {
+		calls++
+		return errTransient
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, calls)
+}
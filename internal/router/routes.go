@@ -8,6 +8,63 @@ import (
 func SummaryRoutes(app *fiber.App, h handler.SummaryHandler) {
 	api := app.Group("/summary")
 	api.Post("/sync", h.SyncSummary)
+	api.Post("/sync/batch", h.SyncBatch)
 	api.Get("/summaries", h.GetSummaries)
 	api.Get("/summaries/:id", h.GetSummaryByID)
+	api.Delete("/summaries/:id", h.DeleteSummary)
+	api.Post("/summaries/:id/restore", h.RestoreSummary)
+	api.Get("/summaries/:id/schemas", h.GetSchemas)
+	api.Get("/summaries/:id/schemas/:schema/tables", h.GetTables)
+	api.Get("/summaries/:id/schemas/:schema/tables/:table", h.GetTable)
+	api.Get("/sources/:id/snapshots", h.GetSnapshots)
+	api.Get("/sources/:id/snapshots/:snapshotId", h.GetSnapshot)
+	api.Get("/diff", h.DiffSummaries)
+	api.Get("/jobs/:id", h.GetJob)
+}
+
+func ScheduleRoutes(app *fiber.App, h handler.ScheduleHandler) {
+	api := app.Group("/summary")
+	api.Post("/schedules", h.CreateSchedule)
+	api.Get("/schedules", h.GetSchedules)
+	api.Get("/schedules/:id", h.GetSchedule)
+	api.Post("/schedules/:id/pause", h.PauseSchedule)
+	api.Post("/schedules/:id/resume", h.ResumeSchedule)
+	api.Delete("/schedules/:id", h.DeleteSchedule)
+}
+
+func SourceRoutes(app *fiber.App, h handler.SourceHandler) {
+	api := app.Group("/summary")
+	api.Post("/sources", h.CreateSource)
+	api.Get("/sources", h.GetSources)
+	api.Get("/sources/:id", h.GetSource)
+	api.Put("/sources/:id", h.UpdateSource)
+	api.Delete("/sources/:id", h.DeleteSource)
+}
+
+func AdminRoutes(app *fiber.App, h handler.AdminHandler) {
+	api := app.Group("/summary/admin")
+	api.Get("/circuits", h.GetCircuits)
+	api.Post("/circuits/:target/reset", h.ResetCircuit)
+}
+
+func ServerRoutes(app *fiber.App, h handler.ServerHandler) {
+	api := app.Group("/summary")
+	api.Post("/servers/discover", h.DiscoverServer)
+	api.Get("/servers", h.GetServers)
+	api.Get("/servers/:id", h.GetServer)
+}
+
+func SearchRoutes(app *fiber.App, h handler.SearchHandler) {
+	api := app.Group("/summary")
+	api.Get("/search", h.Search)
+}
+
+func StatsRoutes(app *fiber.App, h handler.StatsHandler) {
+	api := app.Group("/summary")
+	api.Get("/stats", h.GetFleetStats)
+}
+
+func TrendRoutes(app *fiber.App, h handler.TrendHandler) {
+	api := app.Group("/summary")
+	api.Get("/sources/:id/trends", h.GetSourceTrends)
 }
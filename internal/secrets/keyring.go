@@ -0,0 +1,163 @@
+package secrets
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// envelopeVersion prefixes every ciphertext produced by a Keyring.
+const envelopeVersion = "v1"
+
+// Keyring implements envelope encryption. Each secret is encrypted with its
+// own random data key, and the data key is wrapped with the active key
+// encryption key. Ciphertexts record the ID of the wrapping key, so keys can
+// be rotated: add a new key, make it active, and Rewrap existing secrets
+// before retiring the old one.
+//
+// Ciphertext format: v1.<key id>.<base64 wrapped data key>.<base64 payload>
+type Keyring struct {
+	active string
+	keys   map[string]cipher.AEAD
+}
+
+// NewKeyring builds a keyring from raw keys indexed by ID. activeID must be
+// one of them; new secrets are wrapped with it.
+func NewKeyring(activeID string, keys map[string][]byte) (*Keyring, error) {
+	if _, ok := keys[activeID]; !ok {
+		return nil, fmt.Errorf("active key %q is not in the keyring", activeID)
+	}
+	ring := &Keyring{active: activeID, keys: make(map[string]cipher.AEAD, len(keys))}
+	for id, key := range keys {
+		if id == "" || strings.ContainsAny(id, ".,:") {
+			return nil, fmt.Errorf("invalid key id %q", id)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		ring.keys[id] = aead
+	}
+	return ring, nil
+}
+
+// ParseKeyring parses a comma separated list of id:base64key pairs, such as
+// "2025-06:AbC...,2024-01:XyZ...". When activeID is empty the first key is
+// active.
+func ParseKeyring(spec, activeID string) (*Keyring, error) {
+	keys := make(map[string][]byte)
+	for i, entry := range strings.Split(spec, ",") {
+		id, encoded, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok {
+			return nil, fmt.Errorf("key entry %d: expected id:base64key", i+1)
+		}
+		key, err := ParseKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		if i == 0 && activeID == "" {
+			activeID = id
+		}
+		keys[id] = key
+	}
+	return NewKeyring(activeID, keys)
+}
+
+// ActiveKeyID returns the ID of the key used to wrap new secrets.
+func (k *Keyring) ActiveKeyID() string {
+	return k.active
+}
+
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	dataKey := make([]byte, KeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	payload, err := seal(dataAEAD, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := seal(k.keys[k.active], dataKey)
+	if err != nil {
+		return "", err
+	}
+	return formatEnvelope(k.active, wrapped, payload), nil
+}
+
+func (k *Keyring) Decrypt(ciphertext string) (string, error) {
+	keyID, wrapped, payload, err := parseEnvelope(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	dataKey, err := k.unwrap(keyID, wrapped)
+	if err != nil {
+		return "", err
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", ErrMalformedCiphertext
+	}
+	plaintext, err := open(dataAEAD, payload)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Rewrap re-wraps the data key of ciphertext with the active key. The
+// encrypted payload itself is left untouched.
+func (k *Keyring) Rewrap(ciphertext string) (string, bool, error) {
+	keyID, wrapped, payload, err := parseEnvelope(ciphertext)
+	if err != nil {
+		return "", false, err
+	}
+	if keyID == k.active {
+		return ciphertext, false, nil
+	}
+	dataKey, err := k.unwrap(keyID, wrapped)
+	if err != nil {
+		return "", false, err
+	}
+	rewrapped, err := seal(k.keys[k.active], dataKey)
+	if err != nil {
+		return "", false, err
+	}
+	return formatEnvelope(k.active, rewrapped, payload), true, nil
+}
+
+func (k *Keyring) unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	kek, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, keyID)
+	}
+	return open(kek, wrapped)
+}
+
+func formatEnvelope(keyID string, wrapped, payload []byte) string {
+	return strings.Join([]string{
+		envelopeVersion,
+		keyID,
+		base64.RawURLEncoding.EncodeToString(wrapped),
+		base64.RawURLEncoding.EncodeToString(payload),
+	}, ".")
+}
+
+func parseEnvelope(ciphertext string) (keyID string, wrapped, payload []byte, err error) {
+	parts := strings.Split(ciphertext, ".")
+	if len(parts) != 4 || parts[0] != envelopeVersion {
+		return "", nil, nil, ErrMalformedCiphertext
+	}
+	if wrapped, err = base64.RawURLEncoding.DecodeString(parts[2]); err != nil {
+		return "", nil, nil, ErrMalformedCiphertext
+	}
+	if payload, err = base64.RawURLEncoding.DecodeString(parts[3]); err != nil {
+		return "", nil, nil, ErrMalformedCiphertext
+	}
+	return parts[1], wrapped, payload, nil
+}
@@ -0,0 +1,95 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, KeySize)
+}
+
+func TestKeyring_RoundTrip(t *testing.T) {
+	ring, err := NewKeyring("k1", map[string][]byte{"k1": testKey(1)})
+	assert.NoError(t, err)
+
+	ciphertext, err := ring.Encrypt("s3cr3t pass'word")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(ciphertext, "v1.k1."))
+	assert.NotContains(t, ciphertext, "s3cr3t")
+
+	again, _ := ring.Encrypt("s3cr3t pass'word")
+	assert.NotEqual(t, ciphertext, again, "data keys and nonces must be random")
+
+	plaintext, err := ring.Decrypt(ciphertext)
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t pass'word", plaintext)
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	old, _ := NewKeyring("k1", map[string][]byte{"k1": testKey(1)})
+	ciphertext, _ := old.Encrypt("secret")
+
+	rotated, err := NewKeyring("k2", map[string][]byte{"k1": testKey(1), "k2": testKey(2)})
+	assert.NoError(t, err)
+
+	// Secrets sealed with a retired key stay readable while it is configured.
+	plaintext, err := rotated.Decrypt(ciphertext)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", plaintext)
+
+	rewrapped, changed, err := rotated.Rewrap(ciphertext)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.True(t, strings.HasPrefix(rewrapped, "v1.k2."))
+
+	_, changed, err = rotated.Rewrap(rewrapped)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	retired, _ := NewKeyring("k2", map[string][]byte{"k2": testKey(2)})
+	plaintext, err = retired.Decrypt(rewrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", plaintext)
+
+	_, err = retired.Decrypt(ciphertext)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestKeyring_RejectsTampering(t *testing.T) {
+	ring, _ := NewKeyring("k1", map[string][]byte{"k1": testKey(1)})
+	other, _ := NewKeyring("k1", map[string][]byte{"k1": testKey(3)})
+
+	ciphertext, _ := ring.Encrypt("secret")
+	_, err := other.Decrypt(ciphertext)
+	assert.Error(t, err)
+
+	_, err = ring.Decrypt("not an envelope")
+	assert.ErrorIs(t, err, ErrMalformedCiphertext)
+}
+
+func TestParseKeyring(t *testing.T) {
+	k1 := base64.StdEncoding.EncodeToString(testKey(1))
+	k2 := base64.StdEncoding.EncodeToString(testKey(2))
+
+	ring, err := ParseKeyring("new:"+k2+", old:"+k1, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "new", ring.ActiveKeyID())
+
+	ring, err = ParseKeyring("new:"+k2+",old:"+k1, "old")
+	assert.NoError(t, err)
+	assert.Equal(t, "old", ring.ActiveKeyID())
+
+	_, err = ParseKeyring("new:"+k2, "missing")
+	assert.Error(t, err)
+
+	_, err = ParseKeyring("new:"+base64.StdEncoding.EncodeToString([]byte("short")), "")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	_, err = ParseKeyring(k1, "")
+	assert.Error(t, err)
+}
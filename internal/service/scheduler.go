@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
+	"github.com/lokesh2201013/postgres-data-summary/internal/repository/local"
+	"github.com/lokesh2201013/postgres-data-summary/internal/secrets"
+)
+
+type IScheduleService interface {
+	CreateSchedule(req domain.ScheduleRequest) (*domain.Schedule, error)
+	GetSchedules() ([]domain.Schedule, error)
+	GetSchedule(id string) (*domain.Schedule, error)
+	PauseSchedule(id string) (*domain.Schedule, error)
+	ResumeSchedule(id string) (*domain.Schedule, error)
+	DeleteSchedule(id string) error
+}
+
+var (
+	// ErrInvalidSchedule wraps validation failures of a ScheduleRequest.
+	ErrInvalidSchedule = errors.New("invalid schedule")
+	// ErrScheduleNotFound is returned when a referenced schedule does not exist.
+	ErrScheduleNotFound = errors.New("schedule not found")
+	// ErrPasswordUnreadable is returned when a stored password cannot be
+	// decrypted with the configured keys.
+	ErrPasswordUnreadable = errors.New("stored password cannot be decrypted")
+)
+
+// minScheduleInterval keeps interval and cron schedules from hammering a
+// source.
+const minScheduleInterval = time.Minute
+
+// ScheduleService stores recurring sync schedules and, once started, polls
+// for due schedules and queues a sync job for each of them. Target passwords
+// are encrypted with cipher; a nil cipher only allows passwordless targets.
+type ScheduleService struct {
+	repo    local.ScheduleRepository
+	jobs    IJobService
+	sources ISourceService
+	cipher  secrets.Cipher
+	now     func() time.Time
+}
+
+func NewScheduleService(repo local.ScheduleRepository, jobs IJobService, sources ISourceService, cipher secrets.Cipher) *ScheduleService {
+	return &ScheduleService{repo: repo, jobs: jobs, sources: sources, cipher: cipher, now: time.Now}
+}
+
+// Start checks for due schedules every tick until ctx is cancelled. Because
+// NextRunAt is persisted, runs missed while the service was down fire on
+// the first tick after a restart.
+func (s *ScheduleService) Start(ctx context.Context, tick time.Duration) {
+	go func() {
+		ticker := time.NewTicker(tick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.runDue()
+			}
+		}
+	}()
+	logger.Log.Info("Scheduler started", zap.Duration("tick", tick))
+}
+
+func (s *ScheduleService) runDue() {
+	now := s.now()
+	due, err := s.repo.GetDueSchedules(now)
+	if err != nil {
+		logger.Log.Error("GetDueSchedules failed", zap.Error(err))
+		return
+	}
+
+	for i := range due {
+		schedule := &due[i]
+		next, _ := nextRun(schedule, now)
+		target, err := s.target(schedule)
+		if err != nil {
+			s.recordError(schedule, err, next)
+			continue
+		}
+		job, err := s.jobs.EnqueueSync(domain.SyncRequest{SourceID: schedule.SourceID, ConnectionDetails: target})
+		if errors.Is(err, ErrQueueFull) {
+			// Leave NextRunAt untouched so the schedule is retried next tick.
+			logger.Log.Warn("Scheduled sync could not be queued", zap.String("scheduleID", schedule.ID), zap.Error(err))
+			continue
+		}
+		if err != nil {
+			s.recordError(schedule, err, next)
+			continue
+		}
+
+		if err := s.repo.RecordScheduleRun(schedule.ID, now, job.ID, next); err != nil {
+			logger.Log.Error("RecordScheduleRun failed", zap.String("scheduleID", schedule.ID), zap.Error(err))
+			continue
+		}
+		logger.Log.Info("Scheduled sync queued",
+			zap.String("scheduleID", schedule.ID),
+			zap.String("jobID", job.ID),
+			zap.Time("nextRunAt", next))
+	}
+}
+
+// recordError stores why a due schedule could not be queued. Errors that
+// will not clear by themselves, such as a deleted source or a password no
+// configured key can decrypt, pause the schedule until it is resumed; any
+// other error skips this run and keeps the schedule on its next one.
+func (s *ScheduleService) recordError(schedule *domain.Schedule, err error, next time.Time) {
+	pause := errors.Is(err, ErrSourceNotFound) || errors.Is(err, ErrSecretsDisabled) || errors.Is(err, ErrPasswordUnreadable)
+	if pause {
+		next = schedule.NextRunAt
+	}
+	logger.Log.Error("Scheduled sync could not be queued",
+		zap.String("scheduleID", schedule.ID),
+		zap.Bool("paused", pause),
+		zap.Time("nextRunAt", next),
+		zap.Error(err))
+	if err := s.repo.RecordScheduleError(schedule.ID, err.Error(), next, pause); err != nil {
+		logger.Log.Error("RecordScheduleError failed", zap.String("scheduleID", schedule.ID), zap.Error(err))
+	}
+}
+
+func (s *ScheduleService) CreateSchedule(req domain.ScheduleRequest) (*domain.Schedule, error) {
+	target := req.Target
+	switch {
+	case req.SourceID != "" && target.Host != "":
+		return nil, fmt.Errorf("%w: set either source_id or target, not both", ErrInvalidSchedule)
+	case req.SourceID == "" && (target.Host == "" || target.User == "" || target.DBName == ""):
+		return nil, fmt.Errorf("%w: source_id or target host, user and dbname are required", ErrInvalidSchedule)
+	}
+
+	if req.SourceID != "" {
+		if _, err := s.sources.GetSource(req.SourceID); err != nil {
+			if errors.Is(err, ErrSourceNotFound) {
+				return nil, fmt.Errorf("%w: %w %s", ErrInvalidSchedule, ErrSourceNotFound, req.SourceID)
+			}
+			return nil, err
+		}
+	}
+
+	schedule := &domain.Schedule{
+		ID:        uuid.NewString(),
+		SourceID:  req.SourceID,
+		Target:    target,
+		Cron:      req.Cron,
+		CreatedAt: s.now(),
+	}
+	if target.Password != "" {
+		if s.cipher == nil {
+			return nil, ErrSecretsDisabled
+		}
+		ciphertext, err := s.cipher.Encrypt(target.Password)
+		if err != nil {
+			return nil, err
+		}
+		schedule.TargetPasswordCiphertext = ciphertext
+		schedule.Target.Password = ""
+	}
+	switch {
+	case req.Cron != "" && req.Interval != "":
+		return nil, fmt.Errorf("%w: set either cron or interval, not both", ErrInvalidSchedule)
+	case req.Interval != "":
+		interval, err := time.ParseDuration(req.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+		}
+		if interval < minScheduleInterval {
+			return nil, fmt.Errorf("%w: interval must be at least %s", ErrInvalidSchedule, minScheduleInterval)
+		}
+		schedule.IntervalSeconds = int64(interval / time.Second)
+	case req.Cron == "":
+		return nil, fmt.Errorf("%w: cron or interval is required", ErrInvalidSchedule)
+	}
+
+	next, err := nextRun(schedule, schedule.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+	if schedule.Cron != "" {
+		if after, _ := nextRun(schedule, next); after.Sub(next) < minScheduleInterval {
+			return nil, fmt.Errorf("%w: cron must not run more often than every %s", ErrInvalidSchedule, minScheduleInterval)
+		}
+	}
+	schedule.NextRunAt = next
+
+	if err := s.repo.CreateSchedule(schedule); err != nil {
+		logger.Log.Error("CreateSchedule failed", zap.Error(err))
+		return nil, err
+	}
+	logger.Log.Info("Schedule created",
+		zap.String("scheduleID", schedule.ID),
+		zap.String("sourceID", schedule.SourceID),
+		zap.String("host", target.Host),
+		zap.String("dbname", target.DBName),
+		zap.Time("nextRunAt", next))
+	return schedule, nil
+}
+
+func (s *ScheduleService) GetSchedules() ([]domain.Schedule, error) {
+	schedules, err := s.repo.GetSchedules()
+	if err != nil {
+		logger.Log.Error("GetSchedules failed", zap.Error(err))
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (s *ScheduleService) GetSchedule(id string) (*domain.Schedule, error) {
+	schedule, err := s.repo.GetScheduleByID(id)
+	if err != nil {
+		logger.Log.Error("GetSchedule failed", zap.String("scheduleID", id), zap.Error(err))
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, ErrScheduleNotFound
+	}
+	return schedule, nil
+}
+
+func (s *ScheduleService) PauseSchedule(id string) (*domain.Schedule, error) {
+	return s.update(id, func(schedule *domain.Schedule) error {
+		schedule.Paused = true
+		return nil
+	})
+}
+
+// ResumeSchedule reactivates a schedule and clears its last error. Runs
+// missed while it was paused are skipped; the next run is computed from the
+// current time.
+func (s *ScheduleService) ResumeSchedule(id string) (*domain.Schedule, error) {
+	return s.update(id, func(schedule *domain.Schedule) error {
+		next, err := nextRun(schedule, s.now())
+		if err != nil {
+			return err
+		}
+		schedule.Paused = false
+		schedule.NextRunAt = next
+		schedule.LastError = ""
+		return nil
+	})
+}
+
+func (s *ScheduleService) DeleteSchedule(id string) error {
+	deleted, err := s.repo.DeleteSchedule(id)
+	if err != nil {
+		logger.Log.Error("DeleteSchedule failed", zap.String("scheduleID", id), zap.Error(err))
+		return err
+	}
+	if !deleted {
+		return ErrScheduleNotFound
+	}
+	logger.Log.Info("Schedule deleted", zap.String("scheduleID", id))
+	return nil
+}
+
+// RewrapPasswords re-seals every stored target password under the active
+// key and returns how many were rewritten.
+func (s *ScheduleService) RewrapPasswords() (int, error) {
+	if s.cipher == nil {
+		return 0, ErrSecretsDisabled
+	}
+	schedules, err := s.repo.GetSchedulesWithPassword()
+	if err != nil {
+		return 0, err
+	}
+	ciphertexts := make(map[string]string, len(schedules))
+	for _, schedule := range schedules {
+		ciphertexts[schedule.ID] = schedule.TargetPasswordCiphertext
+	}
+	return rewrapSecrets(s.cipher, ciphertexts, s.repo.UpdateSchedulePassword)
+}
+
+// target returns the connection details to sync for schedule with the
+// stored password decrypted.
+func (s *ScheduleService) target(schedule *domain.Schedule) (domain.ConnectionDetails, error) {
+	target := schedule.Target
+	if schedule.TargetPasswordCiphertext == "" {
+		return target, nil
+	}
+	if s.cipher == nil {
+		return domain.ConnectionDetails{}, ErrSecretsDisabled
+	}
+	password, err := s.cipher.Decrypt(schedule.TargetPasswordCiphertext)
+	if err != nil {
+		return domain.ConnectionDetails{}, fmt.Errorf("%w: %v", ErrPasswordUnreadable, err)
+	}
+	target.Password = password
+	return target, nil
+}
+
+func (s *ScheduleService) update(id string, apply func(*domain.Schedule) error) (*domain.Schedule, error) {
+	schedule, err := s.GetSchedule(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := apply(schedule); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateSchedule(schedule); err != nil {
+		logger.Log.Error("UpdateSchedule failed", zap.String("scheduleID", id), zap.Error(err))
+		return nil, err
+	}
+	logger.Log.Info("Schedule updated", zap.String("scheduleID", id), zap.Bool("paused", schedule.Paused))
+	return schedule, nil
+}
+
+// nextRun returns the first run of schedule strictly after from. Cron
+// expressions use the standard five-field syntax and descriptors such as
+// "@daily".
+func nextRun(schedule *domain.Schedule, from time.Time) (time.Time, error) {
+	if schedule.Cron != "" {
+		spec, err := cron.ParseStandard(schedule.Cron)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return spec.Next(from), nil
+	}
+	return from.Add(time.Duration(schedule.IntervalSeconds) * time.Second), nil
+}
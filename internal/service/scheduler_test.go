@@ -0,0 +1,270 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockScheduleRepo struct {
+	mock.Mock
+}
+
+func (m *mockScheduleRepo) CreateSchedule(schedule *domain.Schedule) error {
+	return m.Called(schedule).Error(0)
+}
+
+func (m *mockScheduleRepo) UpdateSchedule(schedule *domain.Schedule) error {
+	return m.Called(schedule).Error(0)
+}
+
+func (m *mockScheduleRepo) GetSchedules() ([]domain.Schedule, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Schedule), args.Error(1)
+}
+
+func (m *mockScheduleRepo) GetScheduleByID(id string) (*domain.Schedule, error) {
+	args := m.Called(id)
+	if s := args.Get(0); s != nil {
+		return s.(*domain.Schedule), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockScheduleRepo) GetDueSchedules(now time.Time) ([]domain.Schedule, error) {
+	args := m.Called(now)
+	return args.Get(0).([]domain.Schedule), args.Error(1)
+}
+
+func (m *mockScheduleRepo) RecordScheduleRun(id string, ranAt time.Time, jobID string, next time.Time) error {
+	return m.Called(id, ranAt, jobID, next).Error(0)
+}
+
+func (m *mockScheduleRepo) RecordScheduleError(id, message string, next time.Time, pause bool) error {
+	return m.Called(id, message, next, pause).Error(0)
+}
+
+func (m *mockScheduleRepo) DeleteSchedule(id string) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockScheduleRepo) GetSchedulesWithPassword() ([]domain.Schedule, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Schedule), args.Error(1)
+}
+
+func (m *mockScheduleRepo) UpdateSchedulePassword(id, ciphertext string) error {
+	return m.Called(id, ciphertext).Error(0)
+}
+
+type mockJobs struct {
+	mock.Mock
+}
+
+func (m *mockJobs) EnqueueSync(req domain.SyncRequest) (*domain.SyncJob, error) {
+	args := m.Called(req)
+	if j := args.Get(0); j != nil {
+		return j.(*domain.SyncJob), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockJobs) SyncBatch(reqs []domain.SyncRequest) (*domain.BatchSyncResponse, error) {
+	args := m.Called(reqs)
+	if r := args.Get(0); r != nil {
+		return r.(*domain.BatchSyncResponse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockJobs) GetJob(id string) (*domain.SyncJob, error) {
+	args := m.Called(id)
+	if j := args.Get(0); j != nil {
+		return j.(*domain.SyncJob), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func newTestScheduler(repo *mockScheduleRepo, jobs *mockJobs, now time.Time) *ScheduleService {
+	s := NewScheduleService(repo, jobs, nil, nil)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+var scheduleTarget = domain.ConnectionDetails{Host: "db", User: "u", DBName: "demo"}
+
+func TestCreateSchedule_Interval(t *testing.T) {
+	repo := new(mockScheduleRepo)
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	s := newTestScheduler(repo, nil, now)
+
+	repo.On("CreateSchedule", mock.AnythingOfType("*domain.Schedule")).Return(nil)
+
+	schedule, err := s.CreateSchedule(domain.ScheduleRequest{Target: scheduleTarget, Interval: "6h"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6*3600), schedule.IntervalSeconds)
+	assert.Equal(t, now.Add(6*time.Hour), schedule.NextRunAt)
+}
+
+func TestCreateSchedule_Cron(t *testing.T) {
+	repo := new(mockScheduleRepo)
+	now := time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC)
+	s := newTestScheduler(repo, nil, now)
+
+	repo.On("CreateSchedule", mock.AnythingOfType("*domain.Schedule")).Return(nil)
+
+	schedule, err := s.CreateSchedule(domain.ScheduleRequest{Target: scheduleTarget, Cron: "0 3 * * *"})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC), schedule.NextRunAt)
+}
+
+func TestCreateSchedule_EncryptsTargetPassword(t *testing.T) {
+	repo := new(mockScheduleRepo)
+	jobs := new(mockJobs)
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	s := newTestScheduler(repo, jobs, now)
+	s.cipher = testCipher(t)
+
+	repo.On("CreateSchedule", mock.AnythingOfType("*domain.Schedule")).Return(nil)
+
+	target := scheduleTarget
+	target.Password = "hunter2"
+	schedule, err := s.CreateSchedule(domain.ScheduleRequest{Target: target, Interval: "1h"})
+	assert.NoError(t, err)
+	assert.Empty(t, schedule.Target.Password)
+	assert.NotEmpty(t, schedule.TargetPasswordCiphertext)
+	assert.NotContains(t, schedule.TargetPasswordCiphertext, "hunter2")
+
+	// The scheduler decrypts the password again when it queues the sync.
+	schedule.NextRunAt = now
+	repo.On("GetDueSchedules", now).Return([]domain.Schedule{*schedule}, nil)
+	jobs.On("EnqueueSync", domain.SyncRequest{ConnectionDetails: target}).Return(&domain.SyncJob{ID: "job-1"}, nil)
+	repo.On("RecordScheduleRun", schedule.ID, now, "job-1", now.Add(time.Hour)).Return(nil)
+
+	s.runDue()
+	jobs.AssertExpectations(t)
+}
+
+func TestCreateSchedule_PasswordWithoutKey(t *testing.T) {
+	s := newTestScheduler(new(mockScheduleRepo), nil, time.Now())
+
+	target := scheduleTarget
+	target.Password = "hunter2"
+	_, err := s.CreateSchedule(domain.ScheduleRequest{Target: target, Interval: "1h"})
+	assert.ErrorIs(t, err, ErrSecretsDisabled)
+}
+
+func TestCreateSchedule_Invalid(t *testing.T) {
+	s := newTestScheduler(new(mockScheduleRepo), nil, time.Now())
+
+	cases := []domain.ScheduleRequest{
+		{Target: scheduleTarget},
+		{SourceID: "src-1", Target: scheduleTarget, Interval: "1h"},
+		{Target: scheduleTarget, Cron: "0 3 * * *", Interval: "1h"},
+		{Target: scheduleTarget, Interval: "10s"},
+		{Target: scheduleTarget, Cron: "not a cron"},
+		{Target: scheduleTarget, Cron: "@every 1s"},
+		{Cron: "@daily"},
+	}
+	for _, req := range cases {
+		_, err := s.CreateSchedule(req)
+		assert.ErrorIs(t, err, ErrInvalidSchedule, "%+v", req)
+	}
+}
+
+func TestCreateSchedule_UnknownSource(t *testing.T) {
+	sources := new(mockSourceRepo)
+	s := newTestScheduler(new(mockScheduleRepo), nil, time.Now())
+	s.sources = NewSourceService(sources, nil)
+
+	sources.On("GetSourceByID", "missing").Return(nil, nil)
+
+	_, err := s.CreateSchedule(domain.ScheduleRequest{SourceID: "missing", Interval: "1h"})
+	assert.ErrorIs(t, err, ErrInvalidSchedule)
+	assert.ErrorIs(t, err, ErrSourceNotFound)
+}
+
+func TestRunDue_QueuesJobsAndAdvances(t *testing.T) {
+	repo := new(mockScheduleRepo)
+	jobs := new(mockJobs)
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	s := newTestScheduler(repo, jobs, now)
+
+	due := []domain.Schedule{
+		{ID: "ok", Target: scheduleTarget, IntervalSeconds: 3600},
+		{ID: "saved", SourceID: "src-1", IntervalSeconds: 60},
+		{ID: "busy", Target: domain.ConnectionDetails{Host: "busy"}, IntervalSeconds: 3600},
+	}
+	repo.On("GetDueSchedules", now).Return(due, nil)
+	jobs.On("EnqueueSync", domain.SyncRequest{ConnectionDetails: scheduleTarget}).Return(&domain.SyncJob{ID: "job-1"}, nil)
+	jobs.On("EnqueueSync", domain.SyncRequest{SourceID: "src-1"}).Return(&domain.SyncJob{ID: "job-2"}, nil)
+	jobs.On("EnqueueSync", domain.SyncRequest{ConnectionDetails: domain.ConnectionDetails{Host: "busy"}}).Return(nil, ErrQueueFull)
+	repo.On("RecordScheduleRun", "ok", now, "job-1", now.Add(time.Hour)).Return(nil)
+	repo.On("RecordScheduleRun", "saved", now, "job-2", now.Add(time.Minute)).Return(nil)
+
+	s.runDue()
+
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "RecordScheduleRun", "busy", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunDue_RecordsErrors(t *testing.T) {
+	repo := new(mockScheduleRepo)
+	jobs := new(mockJobs)
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	s := newTestScheduler(repo, jobs, now)
+	s.cipher = testCipher(t)
+
+	due := []domain.Schedule{
+		{ID: "deleted", SourceID: "gone", IntervalSeconds: 3600, NextRunAt: now},
+		{ID: "garbled", Target: scheduleTarget, TargetPasswordCiphertext: "garbage", IntervalSeconds: 3600, NextRunAt: now},
+		{ID: "flaky", SourceID: "src-1", IntervalSeconds: 3600, NextRunAt: now},
+	}
+	repo.On("GetDueSchedules", now).Return(due, nil)
+	jobs.On("EnqueueSync", domain.SyncRequest{SourceID: "gone"}).Return(nil, ErrSourceNotFound)
+	jobs.On("EnqueueSync", domain.SyncRequest{SourceID: "src-1"}).Return(nil, errors.New("db down"))
+	repo.On("RecordScheduleError", "deleted", ErrSourceNotFound.Error(), now, true).Return(nil)
+	repo.On("RecordScheduleError", "garbled", mock.AnythingOfType("string"), now, true).Return(nil)
+	repo.On("RecordScheduleError", "flaky", "db down", now.Add(time.Hour), false).Return(nil)
+
+	s.runDue()
+
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "RecordScheduleRun", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPauseAndResumeSchedule(t *testing.T) {
+	repo := new(mockScheduleRepo)
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	s := newTestScheduler(repo, nil, now)
+
+	stored := &domain.Schedule{ID: "s1", IntervalSeconds: 600, NextRunAt: now.Add(-time.Hour)}
+	repo.On("GetScheduleByID", "s1").Return(stored, nil)
+	repo.On("UpdateSchedule", stored).Return(nil)
+
+	paused, err := s.PauseSchedule("s1")
+	assert.NoError(t, err)
+	assert.True(t, paused.Paused)
+
+	stored.LastError = "source not found"
+	resumed, err := s.ResumeSchedule("s1")
+	assert.NoError(t, err)
+	assert.False(t, resumed.Paused)
+	assert.Empty(t, resumed.LastError)
+	assert.Equal(t, now.Add(10*time.Minute), resumed.NextRunAt)
+}
+
+func TestDeleteSchedule_NotFound(t *testing.T) {
+	repo := new(mockScheduleRepo)
+	s := newTestScheduler(repo, nil, time.Now())
+
+	repo.On("DeleteSchedule", "missing").Return(false, nil)
+
+	err := s.DeleteSchedule("missing")
+	assert.True(t, errors.Is(err, ErrScheduleNotFound))
+}
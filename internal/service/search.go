@@ -0,0 +1,49 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
+	"github.com/lokesh2201013/postgres-data-summary/internal/repository/local"
+)
+
+type ISearchService interface {
+	Search(query domain.SearchQuery, page, pageSize int) (*domain.SearchPage, error)
+}
+
+// SearchService finds tables and schemas by name across every stored
+// summary, so questions like "which database has an invoices table?" do not
+// require loading each summary.
+type SearchService struct {
+	repo local.SearchRepository
+}
+
+func NewSearchService(repo local.SearchRepository) *SearchService {
+	return &SearchService{repo: repo}
+}
+
+// Search returns a page of the tables and schemas whose names match
+// query.Q, best matches first.
+func (s *SearchService) Search(query domain.SearchQuery, page, pageSize int) (*domain.SearchPage, error) {
+	query.Q = strings.TrimSpace(query.Q)
+	if query.Q == "" {
+		return nil, fmt.Errorf("%w: q is required", ErrInvalidQuery)
+	}
+	switch query.Kind {
+	case "", domain.MatchTable, domain.MatchSchema:
+	default:
+		return nil, fmt.Errorf("%w: kind must be %s or %s, got %q", ErrInvalidQuery, domain.MatchTable, domain.MatchSchema, query.Kind)
+	}
+
+	result, err := s.repo.Search(query, page, pageSize)
+	if err != nil {
+		logger.Log.Error("Search failed", zap.String("q", query.Q), zap.Error(err))
+		return nil, err
+	}
+	logger.Log.Info("Search succeeded", zap.String("q", query.Q), zap.Int64("total", result.Total))
+	return result, nil
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+)
+
+type mockSearchRepo struct {
+	mock.Mock
+}
+
+func (m *mockSearchRepo) Search(query domain.SearchQuery, page, pageSize int) (*domain.SearchPage, error) {
+	args := m.Called(query, page, pageSize)
+	if p := args.Get(0); p != nil {
+		return p.(*domain.SearchPage), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestSearch_TrimsAndValidates(t *testing.T) {
+	repo := new(mockSearchRepo)
+	search := NewSearchService(repo)
+
+	repo.On("Search", domain.SearchQuery{Q: "invoices"}, 1, 10).Return(&domain.SearchPage{Total: 2}, nil)
+
+	result, err := search.Search(domain.SearchQuery{Q: "  invoices "}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), result.Total)
+
+	_, err = search.Search(domain.SearchQuery{Q: "   "}, 1, 10)
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+	_, err = search.Search(domain.SearchQuery{Q: "id", Kind: "column"}, 1, 10)
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+	repo.AssertNumberOfCalls(t, "Search", 1)
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
+	"github.com/lokesh2201013/postgres-data-summary/internal/repository/external"
+	"github.com/lokesh2201013/postgres-data-summary/internal/repository/local"
+)
+
+type IServerService interface {
+	DiscoverServer(ctx context.Context, details domain.ConnectionDetails) (*domain.ServerDiscovery, error)
+	GetServers(page, pageSize int) ([]domain.Server, error)
+	GetServer(id string) (*domain.Server, error)
+}
+
+var (
+	// ErrInvalidServer wraps validation failures of discovery requests.
+	ErrInvalidServer = errors.New("invalid server")
+	// ErrServerNotFound is returned when a referenced server does not exist.
+	ErrServerNotFound = errors.New("server not found")
+	// ErrListDatabases wraps failures to enumerate the databases of a server.
+	ErrListDatabases = errors.New("list databases")
+	// ErrServerUnavailable is returned without contacting a server whose
+	// circuit breaker is open.
+	ErrServerUnavailable = errors.New("server unavailable")
+)
+
+// ServerService discovers the databases of a PostgreSQL server and queues a
+// sync of each of them, grouping their sources under a Server record.
+type ServerService struct {
+	repo   local.ServerRepository
+	client external.SummaryClient
+	jobs   IJobService
+	now    func() time.Time
+}
+
+func NewServerService(repo local.ServerRepository, client external.SummaryClient, jobs IJobService) *ServerService {
+	return &ServerService{repo: repo, client: client, jobs: jobs, now: time.Now}
+}
+
+// DiscoverServer lists the non-template databases of the server addressed by
+// details, records them under the server and queues a sync of each with the
+// same credentials, MaxBatchSize at a time. It does not wait for the syncs.
+// DBName is optional and only selects the database connected to for the
+// listing.
+func (s *ServerService) DiscoverServer(ctx context.Context, details domain.ConnectionDetails) (*domain.ServerDiscovery, error) {
+	if details.Host == "" || details.User == "" {
+		return nil, fmt.Errorf("%w: host and user are required", ErrInvalidServer)
+	}
+
+	databases, err := s.client.ListDatabases(ctx, details)
+	if err != nil {
+		logger.Log.Warn("ListDatabases failed", zap.String("host", details.Host), zap.Error(err))
+		if errors.Is(err, external.ErrCircuitOpen) {
+			return nil, fmt.Errorf("%w: %w", ErrServerUnavailable, err)
+		}
+		return nil, fmt.Errorf("%w: %w", ErrListDatabases, err)
+	}
+	logger.Log.Info("Discovered databases", zap.String("host", details.Host), zap.Strings("databases", databases))
+
+	server, err := s.repo.FindOrCreateServer(details.Host, details.PortOrDefault())
+	if err != nil {
+		logger.Log.Error("FindOrCreateServer failed", zap.String("host", details.Host), zap.Error(err))
+		return nil, err
+	}
+	discoveredAt := s.now()
+	if err := s.repo.RecordDiscovery(server.ID, discoveredAt, details.User, databases); err != nil {
+		logger.Log.Error("RecordDiscovery failed", zap.String("serverID", server.ID), zap.Error(err))
+		return nil, err
+	}
+	server.LastDiscoveredAt = &discoveredAt
+
+	discovery := &domain.ServerDiscovery{Server: *server, Databases: databases, Results: []domain.BatchSyncResult{}}
+	for start := 0; start < len(databases); start += MaxBatchSize {
+		names := databases[start:min(start+MaxBatchSize, len(databases))]
+		reqs := make([]domain.SyncRequest, len(names))
+		for i, name := range names {
+			target := details
+			target.DBName = name
+			reqs[i] = domain.SyncRequest{ConnectionDetails: target}
+		}
+		batch, err := s.jobs.SyncBatch(reqs)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range batch.Results {
+			result.Index += start
+			discovery.Results = append(discovery.Results, result)
+		}
+		discovery.Queued += batch.Queued
+		discovery.Failed += batch.Failed
+	}
+
+	logger.Log.Info("Server discovery finished",
+		zap.String("serverID", server.ID),
+		zap.Int("queued", discovery.Queued),
+		zap.Int("failed", discovery.Failed))
+	return discovery, nil
+}
+
+func (s *ServerService) GetServers(page, pageSize int) ([]domain.Server, error) {
+	servers, err := s.repo.GetServers(page, pageSize)
+	if err != nil {
+		logger.Log.Error("GetServers failed", zap.Error(err))
+		return nil, err
+	}
+	return servers, nil
+}
+
+func (s *ServerService) GetServer(id string) (*domain.Server, error) {
+	server, err := s.repo.GetServerByID(id)
+	if err != nil {
+		logger.Log.Error("GetServer failed", zap.String("serverID", id), zap.Error(err))
+		return nil, err
+	}
+	if server == nil {
+		return nil, ErrServerNotFound
+	}
+	return server, nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/repository/external"
+)
+
+type mockServerRepo struct {
+	mock.Mock
+}
+
+func (m *mockServerRepo) FindOrCreateServer(host string, port int) (*domain.Server, error) {
+	args := m.Called(host, port)
+	if s := args.Get(0); s != nil {
+		return s.(*domain.Server), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockServerRepo) GetServers(page, pageSize int) ([]domain.Server, error) {
+	args := m.Called(page, pageSize)
+	return args.Get(0).([]domain.Server), args.Error(1)
+}
+
+func (m *mockServerRepo) GetServerByID(id string) (*domain.Server, error) {
+	args := m.Called(id)
+	if s := args.Get(0); s != nil {
+		return s.(*domain.Server), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockServerRepo) RecordDiscovery(id string, discoveredAt time.Time, user string, dbNames []string) error {
+	return m.Called(id, discoveredAt, user, dbNames).Error(0)
+}
+
+func TestDiscoverServer_QueuesEveryDatabase(t *testing.T) {
+	repo := new(mockServerRepo)
+	client := new(mockExternalClient)
+	jobs := new(mockJobs)
+	servers := NewServerService(repo, client, jobs)
+	discoveredAt := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	servers.now = func() time.Time { return discoveredAt }
+
+	details := domain.ConnectionDetails{Host: "pg1", User: "dba", Password: "secret"}
+	client.On("ListDatabases", details).Return([]string{"billing", "sales"}, nil)
+	repo.On("FindOrCreateServer", "pg1", domain.DefaultPort).Return(&domain.Server{ID: "srv-1", Host: "pg1", Port: domain.DefaultPort}, nil)
+	repo.On("RecordDiscovery", "srv-1", discoveredAt, "dba", []string{"billing", "sales"}).Return(nil)
+	jobs.On("SyncBatch", []domain.SyncRequest{
+		{ConnectionDetails: domain.ConnectionDetails{Host: "pg1", User: "dba", Password: "secret", DBName: "billing"}},
+		{ConnectionDetails: domain.ConnectionDetails{Host: "pg1", User: "dba", Password: "secret", DBName: "sales"}},
+	}).Return(&domain.BatchSyncResponse{
+		Queued: 1,
+		Failed: 1,
+		Results: []domain.BatchSyncResult{
+			{Index: 0, Host: "pg1", DBName: "billing", JobID: "job-1", Status: domain.JobQueued},
+			{Index: 1, Host: "pg1", DBName: "sales", Status: domain.JobFailed, Error: "sync queue is full"},
+		},
+	}, nil)
+
+	discovery, err := servers.DiscoverServer(context.Background(), details)
+	assert.NoError(t, err)
+	assert.Equal(t, "srv-1", discovery.Server.ID)
+	assert.Equal(t, discoveredAt, *discovery.Server.LastDiscoveredAt)
+	assert.Equal(t, []string{"billing", "sales"}, discovery.Databases)
+	assert.Equal(t, 1, discovery.Queued)
+	assert.Equal(t, 1, discovery.Failed)
+	assert.Len(t, discovery.Results, 2)
+	repo.AssertExpectations(t)
+}
+
+func TestDiscoverServer_QueuesLargeServersInBatches(t *testing.T) {
+	repo := new(mockServerRepo)
+	client := new(mockExternalClient)
+	jobs := new(mockJobs)
+	servers := NewServerService(repo, client, jobs)
+
+	details := domain.ConnectionDetails{Host: "pg1", User: "dba"}
+	databases := make([]string, MaxBatchSize+2)
+	for i := range databases {
+		databases[i] = fmt.Sprintf("db%03d", i)
+	}
+	client.On("ListDatabases", details).Return(databases, nil)
+	repo.On("FindOrCreateServer", "pg1", domain.DefaultPort).Return(&domain.Server{ID: "srv-1"}, nil)
+	repo.On("RecordDiscovery", "srv-1", mock.Anything, "dba", databases).Return(nil)
+	for _, chunk := range [][]string{databases[:MaxBatchSize], databases[MaxBatchSize:]} {
+		reqs := make([]domain.SyncRequest, len(chunk))
+		response := &domain.BatchSyncResponse{Queued: len(chunk)}
+		for i, name := range chunk {
+			reqs[i] = domain.SyncRequest{ConnectionDetails: domain.ConnectionDetails{Host: "pg1", User: "dba", DBName: name}}
+			response.Results = append(response.Results, domain.BatchSyncResult{Index: i, DBName: name, Status: domain.JobQueued})
+		}
+		jobs.On("SyncBatch", reqs).Return(response, nil).Once()
+	}
+
+	discovery, err := servers.DiscoverServer(context.Background(), details)
+	assert.NoError(t, err)
+	assert.Equal(t, MaxBatchSize+2, discovery.Queued)
+	assert.Len(t, discovery.Results, MaxBatchSize+2)
+	last := discovery.Results[MaxBatchSize+1]
+	assert.Equal(t, MaxBatchSize+1, last.Index)
+	assert.Equal(t, databases[MaxBatchSize+1], last.DBName)
+	jobs.AssertNumberOfCalls(t, "SyncBatch", 2)
+}
+
+func TestDiscoverServer_ListFails(t *testing.T) {
+	client := new(mockExternalClient)
+	servers := NewServerService(new(mockServerRepo), client, new(mockJobs))
+
+	details := domain.ConnectionDetails{Host: "pg1", User: "dba"}
+	client.On("ListDatabases", details).Return(nil, errors.New("connection refused"))
+	open := domain.ConnectionDetails{Host: "pg2", User: "dba"}
+	client.On("ListDatabases", open).Return(nil, fmt.Errorf("%w for pg2:5432", external.ErrCircuitOpen))
+
+	_, err := servers.DiscoverServer(context.Background(), details)
+	assert.ErrorIs(t, err, ErrListDatabases)
+
+	_, err = servers.DiscoverServer(context.Background(), open)
+	assert.ErrorIs(t, err, ErrServerUnavailable)
+
+	_, err = servers.DiscoverServer(context.Background(), domain.ConnectionDetails{Host: "pg1"})
+	assert.ErrorIs(t, err, ErrInvalidServer)
+}
+
+func TestGetServer_NotFound(t *testing.T) {
+	repo := new(mockServerRepo)
+	repo.On("GetServerByID", "missing").Return(nil, nil)
+
+	_, err := NewServerService(repo, nil, nil).GetServer("missing")
+	assert.ErrorIs(t, err, ErrServerNotFound)
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
+	"github.com/lokesh2201013/postgres-data-summary/internal/repository/local"
+	"github.com/lokesh2201013/postgres-data-summary/internal/secrets"
+)
+
+type ISourceService interface {
+	CreateSource(details domain.ConnectionDetails) (*domain.Source, error)
+	GetSources(page, pageSize int) ([]domain.Source, error)
+	GetSource(id string) (*domain.Source, error)
+	UpdateSource(id string, details domain.ConnectionDetails) (*domain.Source, error)
+	DeleteSource(id string) error
+	ResolveConnection(id string) (domain.ConnectionDetails, error)
+}
+
+var (
+	// ErrInvalidSource wraps validation failures of a source profile.
+	ErrInvalidSource = errors.New("invalid source")
+	// ErrSourceNotFound is returned when a referenced source does not exist.
+	ErrSourceNotFound = errors.New("source not found")
+	// ErrSourceExists is returned when saving a profile for a database that
+	// already has a registered source.
+	ErrSourceExists = errors.New("source already exists")
+	// ErrSecretsDisabled is returned when a password must be stored or read
+	// but no secret key is configured.
+	ErrSecretsDisabled = errors.New("no secret key configured for stored passwords")
+)
+
+// SourceService manages saved connection profiles. Passwords are encrypted
+// with cipher before they reach the repository; a nil cipher disables
+// password storage.
+type SourceService struct {
+	repo   local.SourceRepository
+	cipher secrets.Cipher
+}
+
+func NewSourceService(repo local.SourceRepository, cipher secrets.Cipher) *SourceService {
+	return &SourceService{repo: repo, cipher: cipher}
+}
+
+// CreateSource saves a connection profile. A database that was synced or
+// discovered without one already has an unregistered source; it is
+// registered in place, keeping its ID and snapshot history. A registered
+// source for the database returns ErrSourceExists.
+func (s *SourceService) CreateSource(details domain.ConnectionDetails) (*domain.Source, error) {
+	if err := validateSource(details); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetSourceByTarget(details.Host, details.PortOrDefault(), details.DBName)
+	if err != nil {
+		logger.Log.Error("GetSourceByTarget failed", zap.Error(err))
+		return nil, err
+	}
+	if existing != nil && existing.Registered {
+		return nil, fmt.Errorf("%w: %s", ErrSourceExists, existing.ID)
+	}
+
+	source := existing
+	if source == nil {
+		source = &domain.Source{
+			ID:     domain.SourceID(details.Host, details.PortOrDefault(), details.DBName),
+			Host:   details.Host,
+			Port:   details.PortOrDefault(),
+			DBName: details.DBName,
+		}
+	}
+	source.User = details.User
+	source.SSLMode = details.SSLMode
+	source.Registered = true
+	if err := s.setPassword(source, details.Password); err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		if err := s.repo.UpdateSource(source, true); err != nil {
+			logger.Log.Error("UpdateSource failed", zap.String("sourceID", source.ID), zap.Error(err))
+			return nil, err
+		}
+		logger.Log.Info("Source registered", zap.String("sourceID", source.ID), zap.String("host", source.Host), zap.String("dbname", source.DBName))
+		return source, nil
+	}
+	if err := s.repo.CreateSource(source); err != nil {
+		logger.Log.Error("CreateSource failed", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Log.Info("Source created", zap.String("sourceID", source.ID), zap.String("host", source.Host), zap.String("dbname", source.DBName))
+	return source, nil
+}
+
+func (s *SourceService) GetSources(page, pageSize int) ([]domain.Source, error) {
+	sources, err := s.repo.GetSources(page, pageSize)
+	if err != nil {
+		logger.Log.Error("GetSources failed", zap.Error(err))
+		return nil, err
+	}
+	return sources, nil
+}
+
+func (s *SourceService) GetSource(id string) (*domain.Source, error) {
+	source, err := s.repo.GetSourceByID(id)
+	if err != nil {
+		logger.Log.Error("GetSource failed", zap.String("sourceID", id), zap.Error(err))
+		return nil, err
+	}
+	if source == nil {
+		return nil, ErrSourceNotFound
+	}
+	return source, nil
+}
+
+// UpdateSource replaces the connection settings of a source and registers
+// it. An empty password keeps the stored one. Moving a source onto a database another
+// source already owns returns ErrSourceExists.
+func (s *SourceService) UpdateSource(id string, details domain.ConnectionDetails) (*domain.Source, error) {
+	if err := validateSource(details); err != nil {
+		return nil, err
+	}
+	source, err := s.GetSource(id)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.repo.GetSourceByTarget(details.Host, details.PortOrDefault(), details.DBName)
+	if err != nil {
+		logger.Log.Error("GetSourceByTarget failed", zap.Error(err))
+		return nil, err
+	}
+	if existing != nil && existing.ID != id {
+		return nil, fmt.Errorf("%w: %s", ErrSourceExists, existing.ID)
+	}
+
+	source.Host = details.Host
+	source.Port = details.PortOrDefault()
+	source.DBName = details.DBName
+	source.User = details.User
+	source.SSLMode = details.SSLMode
+	source.Registered = true
+	if details.Password != "" {
+		if err := s.setPassword(source, details.Password); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateSource(source, details.Password != ""); err != nil {
+		logger.Log.Error("UpdateSource failed", zap.String("sourceID", id), zap.Error(err))
+		return nil, err
+	}
+	logger.Log.Info("Source updated", zap.String("sourceID", id))
+	return source, nil
+}
+
+func (s *SourceService) DeleteSource(id string) error {
+	deleted, err := s.repo.DeleteSource(id)
+	if err != nil {
+		logger.Log.Error("DeleteSource failed", zap.String("sourceID", id), zap.Error(err))
+		return err
+	}
+	if !deleted {
+		return ErrSourceNotFound
+	}
+	logger.Log.Info("Source deleted", zap.String("sourceID", id))
+	return nil
+}
+
+// ResolveConnection returns the connection details of a saved source with
+// its password decrypted, ready to hand to the external client.
+func (s *SourceService) ResolveConnection(id string) (domain.ConnectionDetails, error) {
+	source, err := s.GetSource(id)
+	if err != nil {
+		return domain.ConnectionDetails{}, err
+	}
+
+	port := source.Port
+	details := domain.ConnectionDetails{
+		Host:    source.Host,
+		Port:    &port,
+		User:    source.User,
+		DBName:  source.DBName,
+		SSLMode: source.SSLMode,
+	}
+	if source.HasPassword {
+		if s.cipher == nil {
+			return domain.ConnectionDetails{}, ErrSecretsDisabled
+		}
+		details.Password, err = s.cipher.Decrypt(source.PasswordCiphertext)
+		if err != nil {
+			logger.Log.Error("Failed to decrypt source password", zap.String("sourceID", id), zap.Error(err))
+			return domain.ConnectionDetails{}, fmt.Errorf("%w: %v", ErrPasswordUnreadable, err)
+		}
+	}
+	return details, nil
+}
+
+// RewrapPasswords re-seals every stored source password under the active
+// key and returns how many were rewritten.
+func (s *SourceService) RewrapPasswords() (int, error) {
+	if s.cipher == nil {
+		return 0, ErrSecretsDisabled
+	}
+	sources, err := s.repo.GetSourcesWithPassword()
+	if err != nil {
+		return 0, err
+	}
+	ciphertexts := make(map[string]string, len(sources))
+	for _, source := range sources {
+		ciphertexts[source.ID] = source.PasswordCiphertext
+	}
+	return rewrapSecrets(s.cipher, ciphertexts, s.repo.UpdateSourcePassword)
+}
+
+func (s *SourceService) setPassword(source *domain.Source, password string) error {
+	if password == "" {
+		source.PasswordCiphertext = ""
+		source.HasPassword = false
+		return nil
+	}
+	if s.cipher == nil {
+		return ErrSecretsDisabled
+	}
+	ciphertext, err := s.cipher.Encrypt(password)
+	if err != nil {
+		return err
+	}
+	source.PasswordCiphertext = ciphertext
+	source.HasPassword = true
+	return nil
+}
+
+func validateSource(details domain.ConnectionDetails) error {
+	if details.Host == "" || details.User == "" || details.DBName == "" {
+		return fmt.Errorf("%w: host, user and dbname are required", ErrInvalidSource)
+	}
+	return nil
+}
+
+// rewrapSecrets re-seals each ciphertext, keyed by record ID, under the
+// active key and saves those that changed. It stops at the first failure;
+// records already saved stay rewrapped, so the operation can be re-run.
+func rewrapSecrets(cipher secrets.Cipher, ciphertexts map[string]string, save func(id, ciphertext string) error) (int, error) {
+	rewrapped := 0
+	for id, ciphertext := range ciphertexts {
+		updated, changed, err := cipher.Rewrap(ciphertext)
+		if err != nil {
+			return rewrapped, fmt.Errorf("rewrap %s: %w", id, err)
+		}
+		if !changed {
+			continue
+		}
+		if err := save(id, updated); err != nil {
+			return rewrapped, fmt.Errorf("save %s: %w", id, err)
+		}
+		rewrapped++
+	}
+	return rewrapped, nil
+}
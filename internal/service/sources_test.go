@@ -0,0 +1,212 @@
+package service
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockSourceRepo struct {
+	mock.Mock
+}
+
+func (m *mockSourceRepo) CreateSource(source *domain.Source) error {
+	return m.Called(source).Error(0)
+}
+
+func (m *mockSourceRepo) UpdateSource(source *domain.Source, withPassword bool) error {
+	return m.Called(source, withPassword).Error(0)
+}
+
+func (m *mockSourceRepo) GetSources(page, pageSize int) ([]domain.Source, error) {
+	args := m.Called(page, pageSize)
+	return args.Get(0).([]domain.Source), args.Error(1)
+}
+
+func (m *mockSourceRepo) GetSourceByID(id string) (*domain.Source, error) {
+	args := m.Called(id)
+	if s := args.Get(0); s != nil {
+		return s.(*domain.Source), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockSourceRepo) GetSourceByTarget(host string, port int, dbName string) (*domain.Source, error) {
+	args := m.Called(host, port, dbName)
+	if s := args.Get(0); s != nil {
+		return s.(*domain.Source), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockSourceRepo) DeleteSource(id string) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockSourceRepo) GetSourcesWithPassword() ([]domain.Source, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Source), args.Error(1)
+}
+
+func (m *mockSourceRepo) UpdateSourcePassword(id, ciphertext string) error {
+	return m.Called(id, ciphertext).Error(0)
+}
+
+func testKeyring(t *testing.T, active string, ids ...string) *secrets.Keyring {
+	keys := make(map[string][]byte)
+	for _, id := range ids {
+		// Derive each key from its ID so keyrings built separately agree.
+		keys[id] = bytes.Repeat([]byte(id[len(id)-1:]), secrets.KeySize)
+	}
+	ring, err := secrets.NewKeyring(active, keys)
+	assert.NoError(t, err)
+	return ring
+}
+
+func testCipher(t *testing.T) secrets.Cipher {
+	return testKeyring(t, "k1", "k1")
+}
+
+func TestCreateSource_EncryptsPassword(t *testing.T) {
+	repo := new(mockSourceRepo)
+	sources := NewSourceService(repo, testCipher(t))
+
+	details := domain.ConnectionDetails{Host: "db", User: "u", Password: "hunter2", DBName: "demo"}
+	repo.On("GetSourceByTarget", "db", domain.DefaultPort, "demo").Return(nil, nil)
+	repo.On("CreateSource", mock.AnythingOfType("*domain.Source")).Return(nil)
+
+	source, err := sources.CreateSource(details)
+	assert.NoError(t, err)
+	assert.True(t, source.HasPassword)
+	assert.NotEmpty(t, source.PasswordCiphertext)
+	assert.NotContains(t, source.PasswordCiphertext, "hunter2")
+
+	repo.On("GetSourceByID", source.ID).Return(source, nil)
+	resolved, err := sources.ResolveConnection(source.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", resolved.Password)
+	assert.Equal(t, domain.DefaultPort, *resolved.Port)
+}
+
+func TestCreateSource_Conflict(t *testing.T) {
+	repo := new(mockSourceRepo)
+	sources := NewSourceService(repo, testCipher(t))
+
+	repo.On("GetSourceByTarget", "db", domain.DefaultPort, "demo").Return(&domain.Source{ID: "existing", Registered: true}, nil)
+
+	_, err := sources.CreateSource(domain.ConnectionDetails{Host: "db", User: "u", DBName: "demo"})
+	assert.ErrorIs(t, err, ErrSourceExists)
+}
+
+func TestCreateSource_RegistersSyncedSource(t *testing.T) {
+	repo := new(mockSourceRepo)
+	sources := NewSourceService(repo, testCipher(t))
+
+	synced := &domain.Source{ID: "synced", Host: "db", Port: domain.DefaultPort, DBName: "demo", User: "reader"}
+	repo.On("GetSourceByTarget", "db", domain.DefaultPort, "demo").Return(synced, nil)
+	repo.On("UpdateSource", synced, true).Return(nil)
+
+	source, err := sources.CreateSource(domain.ConnectionDetails{Host: "db", User: "u", Password: "hunter2", DBName: "demo", SSLMode: "require"})
+	assert.NoError(t, err)
+	assert.Equal(t, "synced", source.ID, "the snapshot history stays with the source")
+	assert.True(t, source.Registered)
+	assert.True(t, source.HasPassword)
+	assert.Equal(t, "u", source.User)
+	assert.Equal(t, "require", source.SSLMode)
+	repo.AssertNotCalled(t, "CreateSource", mock.Anything)
+}
+
+func TestCreateSource_PasswordWithoutKey(t *testing.T) {
+	repo := new(mockSourceRepo)
+	sources := NewSourceService(repo, nil)
+
+	repo.On("GetSourceByTarget", "db", domain.DefaultPort, "demo").Return(nil, nil)
+
+	_, err := sources.CreateSource(domain.ConnectionDetails{Host: "db", User: "u", Password: "p", DBName: "demo"})
+	assert.ErrorIs(t, err, ErrSecretsDisabled)
+}
+
+func TestUpdateSource_KeepsPasswordWhenEmpty(t *testing.T) {
+	repo := new(mockSourceRepo)
+	sources := NewSourceService(repo, testCipher(t))
+
+	stored := &domain.Source{ID: "s1", Host: "db", DBName: "demo", PasswordCiphertext: "cipher", HasPassword: true}
+	repo.On("GetSourceByID", "s1").Return(stored, nil)
+	repo.On("GetSourceByTarget", "db2", domain.DefaultPort, "demo").Return(nil, nil)
+	repo.On("UpdateSource", stored, false).Return(nil)
+
+	updated, err := sources.UpdateSource("s1", domain.ConnectionDetails{Host: "db2", User: "u", DBName: "demo"})
+	assert.NoError(t, err)
+	assert.Equal(t, "db2", updated.Host)
+	assert.Equal(t, "cipher", updated.PasswordCiphertext)
+}
+
+func TestUpdateSource_TargetTaken(t *testing.T) {
+	repo := new(mockSourceRepo)
+	sources := NewSourceService(repo, testCipher(t))
+
+	repo.On("GetSourceByID", "s1").Return(&domain.Source{ID: "s1", Host: "db", DBName: "demo"}, nil)
+	repo.On("GetSourceByTarget", "db2", domain.DefaultPort, "demo").Return(&domain.Source{ID: "s2"}, nil)
+	repo.On("GetSourceByTarget", "db", domain.DefaultPort, "demo").Return(&domain.Source{ID: "s1"}, nil)
+	repo.On("UpdateSource", mock.AnythingOfType("*domain.Source"), true).Return(nil)
+
+	_, err := sources.UpdateSource("s1", domain.ConnectionDetails{Host: "db2", User: "u", DBName: "demo"})
+	assert.ErrorIs(t, err, ErrSourceExists)
+
+	// Keeping its own target is not a conflict.
+	_, err = sources.UpdateSource("s1", domain.ConnectionDetails{Host: "db", User: "u2", Password: "new", DBName: "demo"})
+	assert.NoError(t, err)
+	repo.AssertNumberOfCalls(t, "UpdateSource", 1)
+}
+
+func TestEnqueueSync_ResolvesSource(t *testing.T) {
+	sourceRepo := new(mockSourceRepo)
+	jobRepo := new(mockJobRepo)
+	jobs := NewJobService(jobRepo, nil, NewSourceService(sourceRepo, nil), 1, 1)
+
+	sourceRepo.On("GetSourceByID", "s1").Return(&domain.Source{ID: "s1", Host: "db", Port: 6432, User: "u", DBName: "demo"}, nil)
+	sourceRepo.On("GetSourceByID", "missing").Return(nil, nil)
+	jobRepo.On("CreateJob", mock.AnythingOfType("*domain.SyncJob")).Return(nil)
+
+	job, err := jobs.EnqueueSync(domain.SyncRequest{SourceID: "s1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "db", job.Host)
+	queued := <-jobs.queue
+	assert.Equal(t, 6432, *queued.details.Port)
+
+	_, err = jobs.EnqueueSync(domain.SyncRequest{SourceID: "missing"})
+	assert.ErrorIs(t, err, ErrSourceNotFound)
+}
+
+func TestRewrapSourcePasswords(t *testing.T) {
+	old := testKeyring(t, "k1", "k1")
+	current, _ := old.Encrypt("hunter2")
+	stale, _ := old.Encrypt("swordfish")
+
+	rotated := testKeyring(t, "k2", "k1", "k2")
+	current, _, _ = rotated.Rewrap(current)
+
+	repo := new(mockSourceRepo)
+	repo.On("GetSourcesWithPassword").Return([]domain.Source{
+		{ID: "current", PasswordCiphertext: current, HasPassword: true},
+		{ID: "stale", PasswordCiphertext: stale, HasPassword: true},
+	}, nil)
+	var saved string
+	repo.On("UpdateSourcePassword", "stale", mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { saved = args.String(1) }).
+		Return(nil)
+
+	count, err := NewSourceService(repo, rotated).RewrapPasswords()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	repo.AssertNotCalled(t, "UpdateSourcePassword", "current", mock.Anything)
+
+	plaintext, err := testKeyring(t, "k2", "k2").Decrypt(saved)
+	assert.NoError(t, err)
+	assert.Equal(t, "swordfish", plaintext)
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
+	"github.com/lokesh2201013/postgres-data-summary/internal/repository/local"
+)
+
+// MaxStatsTop caps how many of the largest tables and databases a stats
+// request may list.
+const MaxStatsTop = 100
+
+type IStatsService interface {
+	GetFleetStats(top int) (*domain.FleetStats, error)
+}
+
+// StatsService reports how much Postgres is tracked across all sources,
+// counting only the newest snapshot of each.
+type StatsService struct {
+	repo local.StatsRepository
+}
+
+func NewStatsService(repo local.StatsRepository) *StatsService {
+	return &StatsService{repo: repo}
+}
+
+// GetFleetStats returns the fleet totals with the top largest tables and
+// databases.
+func (s *StatsService) GetFleetStats(top int) (*domain.FleetStats, error) {
+	if top < 1 || top > MaxStatsTop {
+		return nil, fmt.Errorf("%w: top must be between 1 and %d, got %d", ErrInvalidQuery, MaxStatsTop, top)
+	}
+
+	stats, err := s.repo.GetFleetStats(top)
+	if err != nil {
+		logger.Log.Error("Failed to get fleet stats", zap.Error(err))
+		return nil, err
+	}
+	logger.Log.Info("Fetched fleet stats", zap.Int64("databases", stats.Databases), zap.Float64("size_mb", stats.SizeMB))
+	return stats, nil
+}
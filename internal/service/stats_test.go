@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+)
+
+type mockStatsRepo struct {
+	mock.Mock
+}
+
+func (m *mockStatsRepo) GetFleetStats(top int) (*domain.FleetStats, error) {
+	args := m.Called(top)
+	if s := args.Get(0); s != nil {
+		return s.(*domain.FleetStats), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestGetFleetStats_ValidatesTop(t *testing.T) {
+	repo := new(mockStatsRepo)
+	stats := NewStatsService(repo)
+
+	repo.On("GetFleetStats", 5).Return(&domain.FleetStats{Databases: 3, SizeMB: 42}, nil)
+
+	result, err := stats.GetFleetStats(5)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), result.Databases)
+
+	_, err = stats.GetFleetStats(0)
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+	_, err = stats.GetFleetStats(MaxStatsTop + 1)
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+	repo.AssertNumberOfCalls(t, "GetFleetStats", 1)
+}
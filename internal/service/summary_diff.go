@@ -0,0 +1,259 @@
+package service
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+)
+
+// diffSummaries compares two summary trees. A removed and an added schema
+// holding the same, non-empty set of table names are reported as a rename;
+// likewise a removed and an added table of the same schema with an identical
+// column signature that includes at least one column besides id. A signature
+// shared by more than one removed or added candidate is ambiguous and never
+// counts as a rename.
+func diffSummaries(from, to *domain.Summary) *domain.SummaryDiff {
+	diff := &domain.SummaryDiff{
+		FromID:         from.ID,
+		ToID:           to.ID,
+		FromSyncedAt:   from.SyncedAt,
+		ToSyncedAt:     to.SyncedAt,
+		AddedSchemas:   []string{},
+		RemovedSchemas: []string{},
+		RenamedSchemas: []domain.SchemaRename{},
+		AddedTables:    []domain.TableRef{},
+		RemovedTables:  []domain.TableRef{},
+		RenamedTables:  []domain.TableRename{},
+		ChangedTables:  []domain.TableChange{},
+	}
+
+	fromSchemas := schemasByName(from)
+	toSchemas := schemasByName(to)
+
+	// pairs maps every schema of from that still exists in to (possibly
+	// under a new name) to its counterpart.
+	pairs := make(map[string]string)
+	var removed, added []string
+	for name := range fromSchemas {
+		if _, ok := toSchemas[name]; ok {
+			pairs[name] = name
+		} else {
+			removed = append(removed, name)
+		}
+	}
+	for name := range toSchemas {
+		if _, ok := fromSchemas[name]; !ok {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(removed)
+	sort.Strings(added)
+
+	renamedTo := matchRenames(removed, added, func(name string) string {
+		return schemaSignature(fromSchemas[name])
+	}, func(name string) string {
+		return schemaSignature(toSchemas[name])
+	})
+
+	for _, name := range removed {
+		if newName, ok := renamedTo[name]; ok {
+			pairs[name] = newName
+			diff.RenamedSchemas = append(diff.RenamedSchemas, domain.SchemaRename{From: name, To: newName})
+			continue
+		}
+		diff.RemovedSchemas = append(diff.RemovedSchemas, name)
+		for _, t := range fromSchemas[name].Tables {
+			diff.RemovedTables = append(diff.RemovedTables, tableRef(name, t))
+		}
+	}
+
+	claimed := make(map[string]bool, len(renamedTo))
+	for _, newName := range renamedTo {
+		claimed[newName] = true
+	}
+	for _, name := range added {
+		if claimed[name] {
+			continue
+		}
+		diff.AddedSchemas = append(diff.AddedSchemas, name)
+		for _, t := range toSchemas[name].Tables {
+			diff.AddedTables = append(diff.AddedTables, tableRef(name, t))
+		}
+	}
+
+	fromNames := make([]string, 0, len(pairs))
+	for name := range pairs {
+		fromNames = append(fromNames, name)
+	}
+	sort.Strings(fromNames)
+	for _, name := range fromNames {
+		diffTables(diff, fromSchemas[name], toSchemas[pairs[name]])
+	}
+
+	fromRows, fromSize := summaryTotals(from)
+	toRows, toSize := summaryTotals(to)
+	diff.RowCountDelta = toRows - fromRows
+	diff.SizeMBDelta = toSize - fromSize
+	return diff
+}
+
+// diffTables compares the tables of a schema pair, reporting them under the
+// schema's name in to.
+func diffTables(diff *domain.SummaryDiff, from, to *domain.Schema) {
+	fromTables := tablesByName(from)
+	toTables := tablesByName(to)
+
+	pairs := make(map[string]string)
+	var removed, added []string
+	for name := range fromTables {
+		if _, ok := toTables[name]; ok {
+			pairs[name] = name
+		} else {
+			removed = append(removed, name)
+		}
+	}
+	for name := range toTables {
+		if _, ok := fromTables[name]; !ok {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(removed)
+	sort.Strings(added)
+
+	renamedTo := matchRenames(removed, added, func(name string) string {
+		return columnSignature(fromTables[name])
+	}, func(name string) string {
+		return columnSignature(toTables[name])
+	})
+
+	for _, name := range removed {
+		if newName, ok := renamedTo[name]; ok {
+			pairs[name] = newName
+			diff.RenamedTables = append(diff.RenamedTables, domain.TableRename{Schema: to.Name, From: name, To: newName})
+			continue
+		}
+		diff.RemovedTables = append(diff.RemovedTables, tableRef(to.Name, *fromTables[name]))
+	}
+
+	claimed := make(map[string]bool, len(renamedTo))
+	for _, newName := range renamedTo {
+		claimed[newName] = true
+	}
+	for _, name := range added {
+		if !claimed[name] {
+			diff.AddedTables = append(diff.AddedTables, tableRef(to.Name, *toTables[name]))
+		}
+	}
+
+	fromNames := make([]string, 0, len(pairs))
+	for name := range pairs {
+		fromNames = append(fromNames, name)
+	}
+	sort.Strings(fromNames)
+	for _, name := range fromNames {
+		before, after := fromTables[name], toTables[pairs[name]]
+		if before.RowCount == after.RowCount && before.SizeMB == after.SizeMB {
+			continue
+		}
+		diff.ChangedTables = append(diff.ChangedTables, domain.TableChange{
+			Schema:        to.Name,
+			Name:          after.Name,
+			RowCountFrom:  before.RowCount,
+			RowCountTo:    after.RowCount,
+			RowCountDelta: after.RowCount - before.RowCount,
+			SizeMBFrom:    before.SizeMB,
+			SizeMBTo:      after.SizeMB,
+			SizeMBDelta:   after.SizeMB - before.SizeMB,
+		})
+	}
+}
+
+// matchRenames pairs a removed name with an added name when both carry the
+// same non-empty signature and no other removed or added name shares it.
+func matchRenames(removed, added []string, fromSig, toSig func(string) string) map[string]string {
+	removedBySig := make(map[string][]string)
+	for _, r := range removed {
+		if sig := fromSig(r); sig != "" {
+			removedBySig[sig] = append(removedBySig[sig], r)
+		}
+	}
+	addedBySig := make(map[string][]string)
+	for _, a := range added {
+		if sig := toSig(a); sig != "" {
+			addedBySig[sig] = append(addedBySig[sig], a)
+		}
+	}
+
+	renamedTo := make(map[string]string)
+	for sig, names := range removedBySig {
+		if candidates := addedBySig[sig]; len(names) == 1 && len(candidates) == 1 {
+			renamedTo[names[0]] = candidates[0]
+		}
+	}
+	return renamedTo
+}
+
+func schemasByName(summary *domain.Summary) map[string]*domain.Schema {
+	schemas := make(map[string]*domain.Schema, len(summary.Schemas))
+	for i := range summary.Schemas {
+		schemas[summary.Schemas[i].Name] = &summary.Schemas[i]
+	}
+	return schemas
+}
+
+func tablesByName(schema *domain.Schema) map[string]*domain.Table {
+	tables := make(map[string]*domain.Table, len(schema.Tables))
+	for i := range schema.Tables {
+		tables[schema.Tables[i].Name] = &schema.Tables[i]
+	}
+	return tables
+}
+
+func schemaSignature(schema *domain.Schema) string {
+	names := make([]string, 0, len(schema.Tables))
+	for _, t := range schema.Tables {
+		names = append(names, t.Name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, "\x00")
+}
+
+// columnSignature returns "" for tables whose only column is id (or that
+// have none): such shapes are too common to identify a table across a rename.
+func columnSignature(table *domain.Table) string {
+	trivial := true
+	for _, c := range table.Columns {
+		if c.Name != "id" {
+			trivial = false
+			break
+		}
+	}
+	if trivial {
+		return ""
+	}
+
+	columns := append([]domain.Column(nil), table.Columns...)
+	sort.Slice(columns, func(i, j int) bool {
+		return columns[i].OrdinalPosition < columns[j].OrdinalPosition
+	})
+	parts := make([]string, 0, len(columns))
+	for _, c := range columns {
+		parts = append(parts, c.Name+" "+c.DataType)
+	}
+	return strings.Join(parts, "\x00")
+}
+
+func tableRef(schema string, table domain.Table) domain.TableRef {
+	return domain.TableRef{Schema: schema, Name: table.Name, RowCount: table.RowCount, SizeMB: table.SizeMB}
+}
+
+func summaryTotals(summary *domain.Summary) (rows int64, sizeMB float64) {
+	for _, schema := range summary.Schemas {
+		for _, table := range schema.Tables {
+			rows += table.RowCount
+			sizeMB += table.SizeMB
+		}
+	}
+	return rows, sizeMB
+}
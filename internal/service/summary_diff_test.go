@@ -0,0 +1,119 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func column(pos int, name, dataType string) domain.Column {
+	return domain.Column{OrdinalPosition: pos, Name: name, DataType: dataType}
+}
+
+func TestDiffSummaries_DetectsDrift(t *testing.T) {
+	userColumns := []domain.Column{column(1, "id", "bigint"), column(2, "email", "text")}
+
+	from := &domain.Summary{ID: "old", Schemas: []domain.Schema{
+		{Name: "public", Tables: []domain.Table{
+			{Name: "users", RowCount: 10, SizeMB: 1, Columns: userColumns},
+			{Name: "orders", RowCount: 5, SizeMB: 2},
+			{Name: "legacy", RowCount: 1, SizeMB: 0.5},
+		}},
+		{Name: "reporting", Tables: []domain.Table{{Name: "daily"}, {Name: "weekly"}}},
+		{Name: "scratch", Tables: []domain.Table{{Name: "tmp", SizeMB: 3}}},
+	}}
+	to := &domain.Summary{ID: "new", Schemas: []domain.Schema{
+		{Name: "public", Tables: []domain.Table{
+			{Name: "accounts", RowCount: 12, SizeMB: 1.5, Columns: userColumns},
+			{Name: "orders", RowCount: 8, SizeMB: 2.5},
+			{Name: "invoices", RowCount: 4, SizeMB: 1},
+		}},
+		{Name: "analytics", Tables: []domain.Table{{Name: "weekly"}, {Name: "daily"}}},
+		{Name: "sales"},
+	}}
+
+	diff := diffSummaries(from, to)
+
+	assert.Equal(t, []domain.SchemaRename{{From: "reporting", To: "analytics"}}, diff.RenamedSchemas)
+	assert.Equal(t, []string{"scratch"}, diff.RemovedSchemas)
+	assert.Equal(t, []string{"sales"}, diff.AddedSchemas)
+
+	assert.Equal(t, []domain.TableRename{{Schema: "public", From: "users", To: "accounts"}}, diff.RenamedTables)
+	assert.Equal(t, []domain.TableRef{{Schema: "public", Name: "invoices", RowCount: 4, SizeMB: 1}}, diff.AddedTables)
+	assert.ElementsMatch(t, []domain.TableRef{
+		{Schema: "scratch", Name: "tmp", SizeMB: 3},
+		{Schema: "public", Name: "legacy", RowCount: 1, SizeMB: 0.5},
+	}, diff.RemovedTables)
+
+	assert.Len(t, diff.ChangedTables, 2)
+	orders := diff.ChangedTables[0]
+	assert.Equal(t, "orders", orders.Name)
+	assert.Equal(t, int64(3), orders.RowCountDelta)
+	assert.InDelta(t, 0.5, orders.SizeMBDelta, 1e-9)
+
+	assert.Equal(t, int64(24-16), diff.RowCountDelta)
+	assert.InDelta(t, 5.0-6.5, diff.SizeMBDelta, 1e-9)
+}
+
+func TestDiffSummaries_NoFalseRenames(t *testing.T) {
+	idOnly := []domain.Column{column(1, "id", "bigint")}
+	tagColumns := []domain.Column{column(1, "id", "bigint"), column(2, "label", "text")}
+
+	from := &domain.Summary{ID: "old", Schemas: []domain.Schema{
+		{Name: "public", Tables: []domain.Table{
+			{Name: "sessions", Columns: idOnly},
+			{Name: "colors", Columns: tagColumns},
+			{Name: "sizes", Columns: tagColumns},
+		}},
+	}}
+	to := &domain.Summary{ID: "new", Schemas: []domain.Schema{
+		{Name: "public", Tables: []domain.Table{
+			{Name: "audit_marks", Columns: idOnly},
+			{Name: "brands", Columns: tagColumns},
+			{Name: "regions", Columns: tagColumns},
+		}},
+	}}
+
+	diff := diffSummaries(from, to)
+
+	assert.Empty(t, diff.RenamedTables)
+	assert.ElementsMatch(t, []domain.TableRef{
+		{Schema: "public", Name: "sessions"},
+		{Schema: "public", Name: "colors"},
+		{Schema: "public", Name: "sizes"},
+	}, diff.RemovedTables)
+	assert.ElementsMatch(t, []domain.TableRef{
+		{Schema: "public", Name: "audit_marks"},
+		{Schema: "public", Name: "brands"},
+		{Schema: "public", Name: "regions"},
+	}, diff.AddedTables)
+}
+
+func TestDiffSummaries_IdenticalSummaries(t *testing.T) {
+	summary := &domain.Summary{ID: "same", Schemas: []domain.Schema{
+		{Name: "public", Tables: []domain.Table{{Name: "users", RowCount: 1, SizeMB: 1}}},
+	}}
+
+	diff := diffSummaries(summary, summary)
+
+	assert.Empty(t, diff.AddedTables)
+	assert.Empty(t, diff.RemovedTables)
+	assert.Empty(t, diff.ChangedTables)
+	assert.Empty(t, diff.RenamedSchemas)
+	assert.Zero(t, diff.SizeMBDelta)
+}
+
+func TestDiffSummaries_NotFound(t *testing.T) {
+	repo := new(mockRepo)
+	service := NewSummaryService(repo, nil, attempts(1))
+
+	repo.On("GetSummaryByID", "a").Return(&domain.Summary{ID: "a"}, nil)
+	repo.On("GetSummaryByID", "b").Return(nil, gorm.ErrRecordNotFound)
+
+	diff, err := service.DiffSummaries("a", "b")
+	assert.Nil(t, diff)
+	assert.True(t, errors.Is(err, ErrSummaryNotFound))
+}
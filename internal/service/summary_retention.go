@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
+)
+
+// DeleteSummary soft-deletes a summary. It disappears from listings,
+// snapshots and diffs until RestoreSummary is called or it is purged.
+func (s *SummaryService) DeleteSummary(id string) error {
+	deleted, err := s.repo.DeleteSummary(id)
+	if err != nil {
+		logger.Log.Error("DeleteSummary failed", zap.String("id", id), zap.Error(err))
+		return err
+	}
+	if !deleted {
+		return ErrSummaryNotFound
+	}
+	logger.Log.Info("Summary deleted", zap.String("id", id))
+	return nil
+}
+
+// RestoreSummary brings back a soft-deleted summary and returns it.
+func (s *SummaryService) RestoreSummary(id string) (*domain.Summary, error) {
+	restored, err := s.repo.RestoreSummary(id)
+	if err != nil {
+		logger.Log.Error("RestoreSummary failed", zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
+	if !restored {
+		return nil, ErrSummaryNotFound
+	}
+	logger.Log.Info("Summary restored", zap.String("id", id))
+
+	summary, err := s.repo.GetSummaryByID(id)
+	if err != nil {
+		return nil, s.lookupError(id, err)
+	}
+	return summary, nil
+}
+
+// PurgeSummary permanently removes a summary, deleted or not, and its whole
+// schema tree.
+func (s *SummaryService) PurgeSummary(id string) error {
+	purged, err := s.repo.PurgeSummary(id)
+	if err != nil {
+		logger.Log.Error("PurgeSummary failed", zap.String("id", id), zap.Error(err))
+		return err
+	}
+	if !purged {
+		return ErrSummaryNotFound
+	}
+	logger.Log.Info("Summary purged", zap.String("id", id))
+	return nil
+}
+
+// PruneSnapshots purges every snapshot synced more than retention ago, and
+// every deleted snapshot deleted more than retention ago, returning how many
+// were removed. The newest live snapshot of each source is always kept.
+func (s *SummaryService) PruneSnapshots(retention time.Duration) (int64, error) {
+	cutoff := s.now().Add(-retention)
+	purged, err := s.repo.PurgeSummariesBefore(cutoff)
+	if err != nil {
+		logger.Log.Error("PruneSnapshots failed", zap.Time("cutoff", cutoff), zap.Error(err))
+		return 0, err
+	}
+	if purged > 0 {
+		logger.Log.Info("Pruned snapshots", zap.Time("cutoff", cutoff), zap.Int64("purged", purged))
+	}
+	return purged, nil
+}
+
+// StartRetention prunes snapshots older than retention once immediately and
+// then every tick until ctx is cancelled.
+func (s *SummaryService) StartRetention(ctx context.Context, retention, tick time.Duration) {
+	go func() {
+		ticker := time.NewTicker(tick)
+		defer ticker.Stop()
+		for {
+			s.PruneSnapshots(retention)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	logger.Log.Info("Snapshot retention started", zap.Duration("retention", retention), zap.Duration("tick", tick))
+}
@@ -1,108 +1,224 @@
 package service
 
 import (
-	//"context"
-	//"fmt"
+	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
 	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
 	"github.com/lokesh2201013/postgres-data-summary/internal/repository/external"
 	"github.com/lokesh2201013/postgres-data-summary/internal/repository/local"
+	"github.com/lokesh2201013/postgres-data-summary/internal/retry"
 	"go.uber.org/zap"
 )
 
 type ISummaryService interface {
-	UpdateSummary(details domain.ConnectionDetails) (*domain.Summary, error)
-	GetSummaries(page, pageSize int) ([]domain.Summary, error)
+	UpdateSummary(ctx context.Context, details domain.ConnectionDetails) (*domain.Summary, error)
+	GetSummaries(query domain.SummaryQuery, page, pageSize int) (*domain.SummaryPage, error)
 	GetSummaryByID(id string) (*domain.Summary, error)
+	GetSnapshots(sourceID string, page, pageSize int) ([]domain.Summary, error)
+	GetSnapshot(sourceID, id string) (*domain.Summary, error)
+	DiffSummaries(fromID, toID string) (*domain.SummaryDiff, error)
+	DeleteSummary(id string) error
+	RestoreSummary(id string) (*domain.Summary, error)
+	PurgeSummary(id string) error
+	GetSchemas(summaryID string, order domain.ListOrder, page, pageSize int) (*domain.SchemaPage, error)
+	GetTables(summaryID, schemaName string, order domain.ListOrder, page, pageSize int) (*domain.TablePage, error)
+	GetTable(summaryID, schemaName, tableName string) (*domain.Table, error)
 }
 
+var (
+	// ErrSummaryNotFound is returned when a referenced summary does not exist.
+	ErrSummaryNotFound = errors.New("summary not found")
+	// ErrSummaryMismatch is returned when the fetched summary describes a
+	// different database than the one requested.
+	ErrSummaryMismatch = errors.New("summary does not match the requested database")
+	// ErrInvalidQuery wraps invalid filters or sort keys of a list.
+	ErrInvalidQuery = errors.New("invalid query")
+	// ErrSchemaNotFound is returned when a summary has no schema of the
+	// requested name.
+	ErrSchemaNotFound = errors.New("schema not found")
+	// ErrTableNotFound is returned when a schema has no table of the
+	// requested name.
+	ErrTableNotFound = errors.New("table not found")
+)
+
 type SummaryService struct {
 	repo     local.SummaryRepository
 	exclient external.SummaryClient
-	retries  int
-	delay    time.Duration
+	policy   retry.Policy
+	now      func() time.Time
 }
 
-func NewSummaryService(repo local.SummaryRepository, client external.SummaryClient, retries int, delay time.Duration) *SummaryService {
+// NewSummaryService returns a SummaryService that retries failed fetches and
+// saves under policy. Fetch errors external.Retryable rejects, such as a
+// wrong password, fail on the first attempt.
+func NewSummaryService(repo local.SummaryRepository, client external.SummaryClient, policy retry.Policy) *SummaryService {
 	return &SummaryService{
 		repo:     repo,
 		exclient: client,
-		retries:  retries,
-		delay:    delay,
+		policy:   policy,
+		now:      time.Now,
 	}
 }
 
-// UpdateSummary fetches summary from external DB with retries and logs
-func (s *SummaryService) UpdateSummary(details domain.ConnectionDetails) (*domain.Summary, error) {
-	logger.Log.Info("Starting UpdateSummary", zap.Any("details", details))
+// UpdateSummary fetches summary from external DB with retries and stores it
+// as a new snapshot of the source database. Cancelling ctx aborts the fetch.
+func (s *SummaryService) UpdateSummary(ctx context.Context, details domain.ConnectionDetails) (*domain.Summary, error) {
+	return s.updateSummary(ctx, details, nil)
+}
 
-	var summary domain.Summary
-	var err error
+// updateSummary implements UpdateSummary, calling onAttempt (when non-nil)
+// before every fetch attempt so callers can track progress.
+func (s *SummaryService) updateSummary(ctx context.Context, details domain.ConnectionDetails, onAttempt func(attempt int)) (*domain.Summary, error) {
+	logger.Log.Info("Starting UpdateSummary", zap.String("host", details.Host), zap.String("dbname", details.DBName))
 
-	for attempt := 1; attempt <= s.retries; attempt++ {
-		summary, err = s.exclient.FetchSummary(details)
-		if err == nil {
-			break
+	var summary domain.Summary
+	err := s.policy.Do(ctx, external.Retryable, func(attempt int) error {
+		if onAttempt != nil {
+			onAttempt(attempt)
+		}
+		var err error
+		summary, err = s.exclient.FetchSummary(ctx, details)
+		if err != nil {
+			logger.Log.Warn("FetchSummary attempt failed",
+				zap.Int("attempt", attempt),
+				zap.String("host", details.Host),
+				zap.String("dbname", details.DBName),
+				zap.Bool("retryable", external.Retryable(err)),
+				zap.Error(err),
+			)
 		}
-		logger.Log.Warn("FetchSummary attempt failed",
-			zap.Int("attempt", attempt),
-			zap.Any("details", details),
-			zap.Error(err),
-		)
-		time.Sleep(s.delay)
+		return err
+	})
+	if err != nil {
+		logger.Log.Error("FetchSummary failed", zap.Error(err))
+		return nil, err
 	}
 
-	if err != nil {
-		logger.Log.Error("FetchSummary failed after retries", zap.Error(err))
+	if err := checkPayload(&summary, details); err != nil {
+		logger.Log.Error("Rejected fetched summary", zap.String("payloadID", summary.ID), zap.Error(err))
 		return nil, err
 	}
+	// The identity of the snapshot is derived from its source when saving;
+	// IDs in the payload are discarded so that two databases can never
+	// collide on one.
+	logger.Log.Info("Fetched summary successfully", zap.String("payloadID", summary.ID))
+	discardIDs(&summary)
 
 	// Mask password for logging
 	details.Password = ""
 	summary.SourceInfo = details
-	summary.SyncedAt = time.Now()
-
-	logger.Log.Info("Fetched summary successfully", zap.String("summaryID", summary.ID))
+	summary.SyncedAt = s.now()
 
 	// Retry DB save with logging
-	for attempt := 1; attempt <= s.retries; attempt++ {
-		err = s.repo.SaveSummary(&summary)
-		if err == nil {
-			logger.Log.Info("Saved summary successfully", zap.String("summaryID", summary.ID))
-			break
+	err = s.policy.Do(ctx, func(err error) bool { return !errors.Is(err, local.ErrSummaryExists) }, func(attempt int) error {
+		err := s.repo.SaveSummary(&summary)
+		if err != nil {
+			logger.Log.Warn("SaveSummary attempt failed",
+				zap.Int("attempt", attempt),
+				zap.String("summaryID", summary.ID),
+				zap.Error(err),
+			)
 		}
-		logger.Log.Warn("SaveSummary attempt failed",
-			zap.Int("attempt", attempt),
-			zap.String("summaryID", summary.ID),
-			zap.Error(err),
-		)
-		time.Sleep(s.delay)
-	}
-
+		return err
+	})
 	if err != nil {
 		logger.Log.Error("SaveSummary failed after retries", zap.String("summaryID", summary.ID), zap.Error(err))
 		return nil, err
 	}
+	logger.Log.Info("Saved summary successfully", zap.String("summaryID", summary.ID))
 
 	return &summary, nil
 }
 
-func (s *SummaryService) GetSummaries(page, pageSize int) ([]domain.Summary, error) {
-	logger.Log.Info("GetSummaries called", zap.Int("page", page), zap.Int("pageSize", pageSize))
-	summaries, err := s.repo.GetSummaries(page, pageSize)
+// checkPayload rejects a summary whose source_info names a different host
+// or database than details. Payloads without source_info are accepted.
+func checkPayload(summary *domain.Summary, details domain.ConnectionDetails) error {
+	info := summary.SourceInfo
+	if (info.Host != "" && info.Host != details.Host) ||
+		(info.DBName != "" && info.DBName != details.DBName) ||
+		(info.Port != nil && *info.Port != details.PortOrDefault()) {
+		return fmt.Errorf("%w: requested %s, got %s", ErrSummaryMismatch,
+			domain.SourceKey(details.Host, details.PortOrDefault(), details.DBName),
+			domain.SourceKey(info.Host, info.PortOrDefault(), info.DBName))
+	}
+	return nil
+}
+
+// discardIDs clears every ID in the summary tree so SaveSummary assigns
+// fresh ones.
+func discardIDs(summary *domain.Summary) {
+	summary.ID = ""
+	for i := range summary.Schemas {
+		schema := &summary.Schemas[i]
+		schema.ID = ""
+		for j := range schema.Tables {
+			table := &schema.Tables[j]
+			table.ID = ""
+			for k := range table.Columns {
+				table.Columns[k].ID = ""
+			}
+			for k := range table.Indexes {
+				table.Indexes[k].ID = ""
+			}
+			for k := range table.Constraints {
+				table.Constraints[k].ID = ""
+			}
+		}
+	}
+}
+
+// GetSummaries lists a page of the summaries matching query. Pages after the
+// first are selected with query.After, the cursor returned with the previous
+// page; page is only used without a cursor.
+func (s *SummaryService) GetSummaries(query domain.SummaryQuery, page, pageSize int) (*domain.SummaryPage, error) {
+	logger.Log.Info("GetSummaries called", zap.Int("page", page), zap.Int("pageSize", pageSize), zap.Any("query", query))
+	if err := validateQuery(query); err != nil {
+		return nil, err
+	}
+	result, err := s.repo.GetSummaries(query, page, pageSize)
 	if err != nil {
 		logger.Log.Error("GetSummaries failed", zap.Error(err))
 		return nil, err
 	}
-	logger.Log.Info("GetSummaries succeeded", zap.Int("count", len(summaries)))
-	return summaries, nil
+	logger.Log.Info("GetSummaries succeeded", zap.Int("count", len(result.Items)), zap.Int64("total", result.Total))
+	return result, nil
+}
+
+func validateQuery(query domain.SummaryQuery) error {
+	switch query.Sort {
+	case "", domain.SortSyncedAt, domain.SortTotalSize, domain.SortTableCount:
+	default:
+		return fmt.Errorf("%w: sort must be one of %s, %s or %s, got %q", ErrInvalidQuery,
+			domain.SortSyncedAt, domain.SortTotalSize, domain.SortTableCount, query.Sort)
+	}
+	if c := query.After; c != nil {
+		sort := query.Sort
+		if sort == "" {
+			sort = domain.SortSyncedAt
+		}
+		if c.Sort != sort || c.Desc != query.Desc {
+			return fmt.Errorf("%w: cursor was issued for a different sort or order", ErrInvalidQuery)
+		}
+	}
+	if query.MinSizeMB < 0 {
+		return fmt.Errorf("%w: minimum size must not be negative", ErrInvalidQuery)
+	}
+	if query.SyncedAfter != nil && query.SyncedBefore != nil && !query.SyncedAfter.Before(*query.SyncedBefore) {
+		return fmt.Errorf("%w: syncedAfter must be before syncedBefore", ErrInvalidQuery)
+	}
+	return nil
 }
 
 func (s *SummaryService) GetSummaryByID(id string) (*domain.Summary, error) {
 	logger.Log.Info("GetSummaryByID called", zap.String("id", id))
 	summary, err := s.repo.GetSummaryByID(id)
+	if local.IsNotFound(err) {
+		summary, err = nil, nil
+	}
 	if err != nil {
 		logger.Log.Error("GetSummaryByID failed", zap.String("id", id), zap.Error(err))
 		return nil, err
@@ -114,3 +230,60 @@ func (s *SummaryService) GetSummaryByID(id string) (*domain.Summary, error) {
 	}
 	return summary, nil
 }
+
+func (s *SummaryService) GetSnapshots(sourceID string, page, pageSize int) ([]domain.Summary, error) {
+	logger.Log.Info("GetSnapshots called", zap.String("sourceID", sourceID), zap.Int("page", page), zap.Int("pageSize", pageSize))
+	snapshots, err := s.repo.GetSnapshots(sourceID, page, pageSize)
+	if err != nil {
+		logger.Log.Error("GetSnapshots failed", zap.String("sourceID", sourceID), zap.Error(err))
+		return nil, err
+	}
+	logger.Log.Info("GetSnapshots succeeded", zap.String("sourceID", sourceID), zap.Int("count", len(snapshots)))
+	return snapshots, nil
+}
+
+func (s *SummaryService) GetSnapshot(sourceID, id string) (*domain.Summary, error) {
+	logger.Log.Info("GetSnapshot called", zap.String("sourceID", sourceID), zap.String("id", id))
+	snapshot, err := s.repo.GetSnapshot(sourceID, id)
+	if err != nil {
+		logger.Log.Error("GetSnapshot failed", zap.String("sourceID", sourceID), zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
+	if snapshot == nil {
+		logger.Log.Warn("Snapshot not found", zap.String("sourceID", sourceID), zap.String("id", id))
+	}
+	return snapshot, nil
+}
+
+// DiffSummaries compares two stored summaries, typically two snapshots of the
+// same source, and reports schema and table drift between them.
+func (s *SummaryService) DiffSummaries(fromID, toID string) (*domain.SummaryDiff, error) {
+	logger.Log.Info("DiffSummaries called", zap.String("from", fromID), zap.String("to", toID))
+
+	from, err := s.repo.GetSummaryByID(fromID)
+	if err != nil {
+		return nil, s.lookupError(fromID, err)
+	}
+	to, err := s.repo.GetSummaryByID(toID)
+	if err != nil {
+		return nil, s.lookupError(toID, err)
+	}
+
+	diff := diffSummaries(from, to)
+	logger.Log.Info("DiffSummaries succeeded",
+		zap.String("from", fromID),
+		zap.String("to", toID),
+		zap.Int("addedTables", len(diff.AddedTables)),
+		zap.Int("removedTables", len(diff.RemovedTables)),
+		zap.Int("changedTables", len(diff.ChangedTables)))
+	return diff, nil
+}
+
+func (s *SummaryService) lookupError(id string, err error) error {
+	if local.IsNotFound(err) {
+		logger.Log.Warn("Summary not found", zap.String("id", id))
+		return fmt.Errorf("%w: %s", ErrSummaryNotFound, id)
+	}
+	logger.Log.Error("Loading summary failed", zap.String("id", id), zap.Error(err))
+	return err
+}
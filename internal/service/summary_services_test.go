@@ -1,19 +1,28 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
-	//"time"
+	"time"
 
 	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/introspect"
+	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
+	"github.com/lokesh2201013/postgres-data-summary/internal/repository/external"
+	"github.com/lokesh2201013/postgres-data-summary/internal/repository/local"
+	"github.com/lokesh2201013/postgres-data-summary/internal/retry"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 func init() {
 
-	logger, _ := zap.NewDevelopment()
-	zap.ReplaceGlobals(logger)
+	log, _ := zap.NewDevelopment()
+	zap.ReplaceGlobals(log)
+	logger.Log = log
 }
 
 type mockRepo struct {
@@ -25,9 +34,12 @@ func (m *mockRepo) SaveSummary(summary *domain.Summary) error {
 	return args.Error(0)
 }
 
-func (m *mockRepo) GetSummaries(page, pageSize int) ([]domain.Summary, error) {
-	args := m.Called(page, pageSize)
-	return args.Get(0).([]domain.Summary), args.Error(1)
+func (m *mockRepo) GetSummaries(query domain.SummaryQuery, page, pageSize int) (*domain.SummaryPage, error) {
+	args := m.Called(query, page, pageSize)
+	if p := args.Get(0); p != nil {
+		return p.(*domain.SummaryPage), args.Error(1)
+	}
+	return nil, args.Error(1)
 }
 
 func (m *mockRepo) GetSummaryByID(id string) (*domain.Summary, error) {
@@ -38,12 +50,69 @@ func (m *mockRepo) GetSummaryByID(id string) (*domain.Summary, error) {
 	return nil, args.Error(1)
 }
 
+func (m *mockRepo) GetSnapshots(sourceID string, page, pageSize int) ([]domain.Summary, error) {
+	args := m.Called(sourceID, page, pageSize)
+	return args.Get(0).([]domain.Summary), args.Error(1)
+}
+
+func (m *mockRepo) GetSnapshot(sourceID, id string) (*domain.Summary, error) {
+	args := m.Called(sourceID, id)
+	if s := args.Get(0); s != nil {
+		return s.(*domain.Summary), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockRepo) DeleteSummary(id string) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockRepo) RestoreSummary(id string) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockRepo) PurgeSummary(id string) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockRepo) PurgeSummariesBefore(cutoff time.Time) (int64, error) {
+	args := m.Called(cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepo) GetSchemas(summaryID string, order domain.ListOrder, page, pageSize int) (*domain.SchemaPage, error) {
+	args := m.Called(summaryID, order, page, pageSize)
+	if p := args.Get(0); p != nil {
+		return p.(*domain.SchemaPage), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockRepo) GetTables(summaryID, schemaName string, order domain.ListOrder, page, pageSize int) (*domain.TablePage, error) {
+	args := m.Called(summaryID, schemaName, order, page, pageSize)
+	if p := args.Get(0); p != nil {
+		return p.(*domain.TablePage), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockRepo) GetTable(summaryID, schemaName, tableName string) (*domain.Table, error) {
+	args := m.Called(summaryID, schemaName, tableName)
+	if t := args.Get(0); t != nil {
+		return t.(*domain.Table), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 // --- Mock External Client ---
 type mockExternalClient struct {
 	mock.Mock
 }
 
-func (m *mockExternalClient) FetchSummary(details domain.ConnectionDetails) (domain.Summary, error) {
+func (m *mockExternalClient) FetchSummary(ctx context.Context, details domain.ConnectionDetails) (domain.Summary, error) {
 	args := m.Called(details)
 	if s := args.Get(0); s != nil {
 		return s.(domain.Summary), args.Error(1)
@@ -51,11 +120,26 @@ func (m *mockExternalClient) FetchSummary(details domain.ConnectionDetails) (dom
 	return domain.Summary{}, args.Error(1)
 }
 
+func (m *mockExternalClient) ListDatabases(ctx context.Context, details domain.ConnectionDetails) ([]string, error) {
+	args := m.Called(details)
+	if d := args.Get(0); d != nil {
+		return d.([]string), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// attempts returns a policy that retries immediately.
+func attempts(n int) retry.Policy {
+	return retry.Policy{MaxAttempts: n}
+}
+
 // --- Tests ---
 func TestUpdateSummary_Success(t *testing.T) {
 	repo := new(mockRepo)
 	client := new(mockExternalClient)
-	service := NewSummaryService(repo, client, 1, 0)
+	service := NewSummaryService(repo, client, attempts(1))
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
 
 	port := 5432
 	details := domain.ConnectionDetails{
@@ -68,11 +152,20 @@ func TestUpdateSummary_Success(t *testing.T) {
 	expectedSummary := domain.Summary{ID: "123", Name: "Test Summary"}
 
 	client.On("FetchSummary", details).Return(expectedSummary, nil)
-	repo.On("SaveSummary", mock.AnythingOfType("*domain.Summary")).Return(nil)
+	repo.On("SaveSummary", mock.AnythingOfType("*domain.Summary")).
+		Run(func(args mock.Arguments) {
+			saved := args.Get(0).(*domain.Summary)
+			// The payload ID is discarded; the repository derives one.
+			assert.Empty(t, saved.ID)
+			saved.ID = "derived"
+		}).
+		Return(nil)
 
-	summary, err := service.UpdateSummary(details)
+	summary, err := service.UpdateSummary(context.Background(), details)
 	assert.NoError(t, err)
-	assert.Equal(t, "123", summary.ID)
+	assert.Equal(t, "derived", summary.ID)
+	assert.Equal(t, "Test Summary", summary.Name)
+	assert.Equal(t, now, summary.SyncedAt)
 	client.AssertExpectations(t)
 	repo.AssertExpectations(t)
 }
@@ -80,20 +173,85 @@ func TestUpdateSummary_Success(t *testing.T) {
 func TestUpdateSummary_FetchError(t *testing.T) {
 	repo := new(mockRepo)
 	client := new(mockExternalClient)
-	service := NewSummaryService(repo, client, 2, 0)
+	service := NewSummaryService(repo, client, attempts(2))
 
 	details := domain.ConnectionDetails{Host: "badhost"}
 	client.On("FetchSummary", details).Return(domain.Summary{}, errors.New("fetch failed"))
 
-	summary, err := service.UpdateSummary(details)
+	summary, err := service.UpdateSummary(context.Background(), details)
 	assert.Nil(t, summary)
 	assert.Error(t, err)
 }
 
+func TestUpdateSummary_ClassifiesFetchErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		calls int
+	}{
+		{"server error", &external.StatusError{StatusCode: 503, Status: "503 Service Unavailable"}, 3},
+		{"timeout", context.DeadlineExceeded, 3},
+		{"rejected login over http", &external.StatusError{StatusCode: 403, Status: "403 Forbidden"}, 1},
+		{"bad request", &external.StatusError{StatusCode: 400, Status: "400 Bad Request"}, 1},
+		{"rejected login", fmt.Errorf("%w: %w: password authentication failed", introspect.ErrConnect, introspect.ErrRejected), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(mockExternalClient)
+			service := NewSummaryService(new(mockRepo), client, attempts(3))
+
+			details := domain.ConnectionDetails{Host: "db"}
+			client.On("FetchSummary", details).Return(domain.Summary{}, tt.err)
+
+			summary, err := service.UpdateSummary(context.Background(), details)
+			assert.Nil(t, summary)
+			assert.ErrorIs(t, err, tt.err)
+			client.AssertNumberOfCalls(t, "FetchSummary", tt.calls)
+		})
+	}
+}
+
+func TestUpdateSummary_RejectsMismatchedPayload(t *testing.T) {
+	client := new(mockExternalClient)
+	service := NewSummaryService(new(mockRepo), client, attempts(1))
+
+	details := domain.ConnectionDetails{Host: "db1", User: "u", DBName: "sales"}
+	client.On("FetchSummary", details).Return(domain.Summary{
+		ID:         "sum123",
+		SourceInfo: domain.ConnectionDetails{Host: "db2", DBName: "billing"},
+	}, nil)
+
+	summary, err := service.UpdateSummary(context.Background(), details)
+	assert.Nil(t, summary)
+	assert.ErrorIs(t, err, ErrSummaryMismatch)
+}
+
+func TestUpdateSummary_DoesNotRetryDuplicateSave(t *testing.T) {
+	repo := new(mockRepo)
+	client := new(mockExternalClient)
+	service := NewSummaryService(repo, client, attempts(3))
+
+	details := domain.ConnectionDetails{Host: "db1", DBName: "sales"}
+	client.On("FetchSummary", details).Return(domain.Summary{
+		Schemas: []domain.Schema{{ID: "payload-schema", Tables: []domain.Table{{ID: "payload-table"}}}},
+	}, nil)
+	repo.On("SaveSummary", mock.AnythingOfType("*domain.Summary")).
+		Run(func(args mock.Arguments) {
+			saved := args.Get(0).(*domain.Summary)
+			assert.Empty(t, saved.Schemas[0].ID)
+			assert.Empty(t, saved.Schemas[0].Tables[0].ID)
+		}).
+		Return(local.ErrSummaryExists)
+
+	_, err := service.UpdateSummary(context.Background(), details)
+	assert.ErrorIs(t, err, local.ErrSummaryExists)
+	repo.AssertNumberOfCalls(t, "SaveSummary", 1)
+}
+
 func TestUpdateSummary_SaveError(t *testing.T) {
 	repo := new(mockRepo)
 	client := new(mockExternalClient)
-	service := NewSummaryService(repo, client, 2, 0)
+	service := NewSummaryService(repo, client, attempts(2))
 
 	details := domain.ConnectionDetails{Host: "localhost"}
 	expectedSummary := domain.Summary{ID: "999"}
@@ -101,30 +259,65 @@ func TestUpdateSummary_SaveError(t *testing.T) {
 	client.On("FetchSummary", details).Return(expectedSummary, nil)
 	repo.On("SaveSummary", mock.AnythingOfType("*domain.Summary")).Return(errors.New("db error"))
 
-	summary, err := service.UpdateSummary(details)
+	summary, err := service.UpdateSummary(context.Background(), details)
 	assert.Nil(t, summary)
 	assert.Error(t, err)
 }
 
+func TestUpdateSummary_StopsWhenCancelled(t *testing.T) {
+	repo := new(mockRepo)
+	client := new(mockExternalClient)
+	service := NewSummaryService(repo, client, attempts(3))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	details := domain.ConnectionDetails{Host: "slowhost"}
+	client.On("FetchSummary", details).Return(domain.Summary{}, context.Canceled)
+
+	summary, err := service.UpdateSummary(ctx, details)
+	assert.Nil(t, summary)
+	assert.ErrorIs(t, err, context.Canceled)
+	client.AssertNumberOfCalls(t, "FetchSummary", 1)
+}
+
 func TestGetSummaries_Success(t *testing.T) {
 	repo := new(mockRepo)
-	service := NewSummaryService(repo, nil, 1, 0)
+	service := NewSummaryService(repo, nil, attempts(1))
 
-	summaries := []domain.Summary{
-		{ID: "1", Name: "Summary1"},
-		{ID: "2", Name: "Summary2"},
+	page := &domain.SummaryPage{
+		Items: []domain.SummaryListItem{{ID: "1", Name: "Summary1"}, {ID: "2", Name: "Summary2"}},
+		Total: 2,
 	}
 
-	repo.On("GetSummaries", 1, 10).Return(summaries, nil)
+	query := domain.SummaryQuery{Host: "pg1", Sort: domain.SortTotalSize, Desc: true}
+	repo.On("GetSummaries", query, 1, 10).Return(page, nil)
 
-	result, err := service.GetSummaries(1, 10)
+	result, err := service.GetSummaries(query, 1, 10)
 	assert.NoError(t, err)
-	assert.Len(t, result, 2)
+	assert.Len(t, result.Items, 2)
+	assert.Equal(t, int64(2), result.Total)
+}
+
+func TestGetSummaries_InvalidQuery(t *testing.T) {
+	service := NewSummaryService(new(mockRepo), nil, attempts(1))
+	now := time.Now()
+
+	for _, query := range []domain.SummaryQuery{
+		{Sort: "name"},
+		{MinSizeMB: -1},
+		{SyncedAfter: &now, SyncedBefore: &now},
+		{After: &domain.SummaryCursor{Sort: domain.SortSyncedAt, ID: "1"}, Sort: domain.SortTableCount},
+		{After: &domain.SummaryCursor{Sort: domain.SortSyncedAt, ID: "1"}, Desc: true},
+	} {
+		_, err := service.GetSummaries(query, 1, 10)
+		assert.ErrorIs(t, err, ErrInvalidQuery)
+	}
 }
 
 func TestGetSummaryByID_Success(t *testing.T) {
 	repo := new(mockRepo)
-	service := NewSummaryService(repo, nil, 1, 0)
+	service := NewSummaryService(repo, nil, attempts(1))
 
 	expected := &domain.Summary{ID: "123", Name: "Demo Summary"}
 	repo.On("GetSummaryByID", "123").Return(expected, nil)
@@ -136,7 +329,7 @@ func TestGetSummaryByID_Success(t *testing.T) {
 
 func TestGetSummaryByID_NotFound(t *testing.T) {
 	repo := new(mockRepo)
-	service := NewSummaryService(repo, nil, 1, 0)
+	service := NewSummaryService(repo, nil, attempts(1))
 
 	repo.On("GetSummaryByID", "999").Return(nil, nil)
 
@@ -144,3 +337,95 @@ func TestGetSummaryByID_NotFound(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Nil(t, summary)
 }
+
+func TestGetSnapshots_Success(t *testing.T) {
+	repo := new(mockRepo)
+	service := NewSummaryService(repo, nil, attempts(1))
+
+	snapshots := []domain.Summary{
+		{ID: "2", SourceID: "src"},
+		{ID: "1", SourceID: "src"},
+	}
+	repo.On("GetSnapshots", "src", 1, 10).Return(snapshots, nil)
+
+	result, err := service.GetSnapshots("src", 1, 10)
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+}
+
+func TestGetSnapshot_NotFound(t *testing.T) {
+	repo := new(mockRepo)
+	service := NewSummaryService(repo, nil, attempts(1))
+
+	repo.On("GetSnapshot", "src", "missing").Return(nil, nil)
+
+	snapshot, err := service.GetSnapshot("src", "missing")
+	assert.NoError(t, err)
+	assert.Nil(t, snapshot)
+}
+
+func TestDeleteAndRestoreSummary(t *testing.T) {
+	repo := new(mockRepo)
+	service := NewSummaryService(repo, nil, attempts(1))
+
+	repo.On("DeleteSummary", "sum-1").Return(true, nil)
+	repo.On("DeleteSummary", "missing").Return(false, nil)
+	repo.On("RestoreSummary", "sum-1").Return(true, nil)
+	repo.On("RestoreSummary", "live").Return(false, nil)
+	repo.On("GetSummaryByID", "sum-1").Return(&domain.Summary{ID: "sum-1"}, nil)
+
+	assert.NoError(t, service.DeleteSummary("sum-1"))
+	assert.ErrorIs(t, service.DeleteSummary("missing"), ErrSummaryNotFound)
+
+	restored, err := service.RestoreSummary("sum-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "sum-1", restored.ID)
+	_, err = service.RestoreSummary("live")
+	assert.ErrorIs(t, err, ErrSummaryNotFound)
+}
+
+func TestGetSummaryByID_Deleted(t *testing.T) {
+	repo := new(mockRepo)
+	service := NewSummaryService(repo, nil, attempts(1))
+
+	repo.On("GetSummaryByID", "gone").Return(nil, fmt.Errorf("load: %w", gorm.ErrRecordNotFound))
+
+	summary, err := service.GetSummaryByID("gone")
+	assert.NoError(t, err)
+	assert.Nil(t, summary)
+}
+
+func TestPruneSnapshots(t *testing.T) {
+	repo := new(mockRepo)
+	service := NewSummaryService(repo, nil, attempts(1))
+	now := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	repo.On("PurgeSummariesBefore", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)).Return(int64(4), nil)
+
+	purged, err := service.PruneSnapshots(30 * 24 * time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), purged)
+}
+
+func TestSummaryTree_NotFoundAndSortValidation(t *testing.T) {
+	repo := new(mockRepo)
+	service := NewSummaryService(repo, nil, attempts(1))
+
+	repo.On("GetSchemas", "missing", domain.ListOrder{}, 1, 10).Return(nil, nil)
+	repo.On("GetTables", "sum-1", "nope", domain.ListOrder{}, 1, 10).Return(nil, nil)
+	repo.On("GetTable", "sum-1", "public", "ghost").Return(nil, nil)
+
+	_, err := service.GetSchemas("missing", domain.ListOrder{}, 1, 10)
+	assert.ErrorIs(t, err, ErrSummaryNotFound)
+	_, err = service.GetTables("sum-1", "nope", domain.ListOrder{}, 1, 10)
+	assert.ErrorIs(t, err, ErrSchemaNotFound)
+	_, err = service.GetTable("sum-1", "public", "ghost")
+	assert.ErrorIs(t, err, ErrTableNotFound)
+
+	_, err = service.GetSchemas("sum-1", domain.ListOrder{Sort: domain.SortRowCount}, 1, 10)
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+	_, err = service.GetTables("sum-1", "public", domain.ListOrder{Sort: domain.SortTableCount}, 1, 10)
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+	repo.AssertNotCalled(t, "GetTables", "sum-1", "public", mock.Anything, 1, 10)
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
+)
+
+// GetSchemas lists a page of the schemas of a summary with their table
+// counts and sizes, without loading any tables.
+func (s *SummaryService) GetSchemas(summaryID string, order domain.ListOrder, page, pageSize int) (*domain.SchemaPage, error) {
+	if err := validateOrder(order, domain.SortName, domain.SortTableCount, domain.SortTotalSize); err != nil {
+		return nil, err
+	}
+	schemas, err := s.repo.GetSchemas(summaryID, order, page, pageSize)
+	if err != nil {
+		logger.Log.Error("GetSchemas failed", zap.String("summaryID", summaryID), zap.Error(err))
+		return nil, err
+	}
+	if schemas == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSummaryNotFound, summaryID)
+	}
+	return schemas, nil
+}
+
+// GetTables lists a page of the tables of one schema of a summary, without
+// their columns, indexes and constraints. A missing summary is reported as
+// ErrSchemaNotFound as well.
+func (s *SummaryService) GetTables(summaryID, schemaName string, order domain.ListOrder, page, pageSize int) (*domain.TablePage, error) {
+	if err := validateOrder(order, domain.SortName, domain.SortRowCount, domain.SortSizeMB); err != nil {
+		return nil, err
+	}
+	tables, err := s.repo.GetTables(summaryID, schemaName, order, page, pageSize)
+	if err != nil {
+		logger.Log.Error("GetTables failed", zap.String("summaryID", summaryID), zap.String("schema", schemaName), zap.Error(err))
+		return nil, err
+	}
+	if tables == nil {
+		return nil, fmt.Errorf("%w: %s in summary %s", ErrSchemaNotFound, schemaName, summaryID)
+	}
+	return tables, nil
+}
+
+// GetTable returns one table of a summary with its columns, indexes and
+// constraints. A missing summary or schema is reported as ErrTableNotFound
+// as well.
+func (s *SummaryService) GetTable(summaryID, schemaName, tableName string) (*domain.Table, error) {
+	table, err := s.repo.GetTable(summaryID, schemaName, tableName)
+	if err != nil {
+		logger.Log.Error("GetTable failed", zap.String("summaryID", summaryID), zap.String("schema", schemaName), zap.String("table", tableName), zap.Error(err))
+		return nil, err
+	}
+	if table == nil {
+		return nil, fmt.Errorf("%w: %s.%s in summary %s", ErrTableNotFound, schemaName, tableName, summaryID)
+	}
+	return table, nil
+}
+
+// validateOrder rejects sort keys other than the allowed ones; an empty key
+// is always allowed.
+func validateOrder(order domain.ListOrder, allowed ...string) error {
+	if order.Sort == "" {
+		return nil
+	}
+	for _, key := range allowed {
+		if order.Sort == key {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: sort must be one of %v, got %q", ErrInvalidQuery, allowed, order.Sort)
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
+)
+
+// MaxBatchSize bounds the number of items in one batch sync.
+const MaxBatchSize = 500
+
+var (
+	// ErrInvalidBatch is returned for an empty or oversized batch.
+	ErrInvalidBatch = errors.New("invalid batch")
+	// ErrInvalidSyncRequest is reported for a sync naming neither a saved
+	// source nor a complete target.
+	ErrInvalidSyncRequest = errors.New("source_id or host, user and dbname are required")
+)
+
+// ValidateSyncRequest checks that req names a saved source or a target with
+// a host, user and dbname. The port is optional and defaults to
+// domain.DefaultPort.
+func ValidateSyncRequest(req domain.SyncRequest) error {
+	if req.SourceID == "" && (req.Host == "" || req.User == "" || req.DBName == "") {
+		return ErrInvalidSyncRequest
+	}
+	return nil
+}
+
+// SyncBatch enqueues every request on the shared sync queue, like
+// EnqueueSync, and returns without waiting for the syncs. An item that is
+// invalid, names an unknown source or finds the queue full is reported as
+// failed; it does not affect the others.
+func (s *JobService) SyncBatch(reqs []domain.SyncRequest) (*domain.BatchSyncResponse, error) {
+	if len(reqs) == 0 || len(reqs) > MaxBatchSize {
+		return nil, fmt.Errorf("%w: between 1 and %d items are required, got %d", ErrInvalidBatch, MaxBatchSize, len(reqs))
+	}
+
+	response := &domain.BatchSyncResponse{Results: make([]domain.BatchSyncResult, len(reqs))}
+	for i, req := range reqs {
+		result := s.enqueueItem(i, req)
+		if result.Status == domain.JobQueued {
+			response.Queued++
+		} else {
+			response.Failed++
+		}
+		response.Results[i] = result
+	}
+	logger.Log.Info("Batch sync queued", zap.Int("queued", response.Queued), zap.Int("failed", response.Failed))
+	return response, nil
+}
+
+func (s *JobService) enqueueItem(index int, req domain.SyncRequest) domain.BatchSyncResult {
+	result := domain.BatchSyncResult{
+		Index:    index,
+		SourceID: req.SourceID,
+		Host:     req.Host,
+		DBName:   req.DBName,
+		Status:   domain.JobFailed,
+	}
+
+	if err := ValidateSyncRequest(req); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	job, err := s.EnqueueSync(req)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Host, result.DBName = job.Host, job.DBName
+	result.JobID = job.ID
+	result.Status = job.Status
+	return result
+}
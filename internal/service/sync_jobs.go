@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
+	"github.com/lokesh2201013/postgres-data-summary/internal/repository/local"
+	"go.uber.org/zap"
+)
+
+type IJobService interface {
+	EnqueueSync(req domain.SyncRequest) (*domain.SyncJob, error)
+	SyncBatch(reqs []domain.SyncRequest) (*domain.BatchSyncResponse, error)
+	GetJob(id string) (*domain.SyncJob, error)
+}
+
+// ErrQueueFull is returned by EnqueueSync when no more jobs can be buffered.
+var ErrQueueFull = errors.New("sync queue is full")
+
+type queuedJob struct {
+	job     *domain.SyncJob
+	details domain.ConnectionDetails
+}
+
+// JobService runs summary syncs in the background on a fixed pool of workers
+// and records their progress as SyncJob rows.
+type JobService struct {
+	repo      local.JobRepository
+	summaries *SummaryService
+	sources   ISourceService
+	workers   int
+	queue     chan queuedJob
+}
+
+func NewJobService(repo local.JobRepository, summaries *SummaryService, sources ISourceService, workers, queueSize int) *JobService {
+	return &JobService{
+		repo:      repo,
+		summaries: summaries,
+		sources:   sources,
+		workers:   workers,
+		queue:     make(chan queuedJob, queueSize),
+	}
+}
+
+// Start fails jobs orphaned by a previous process and launches the workers,
+// which stop once ctx is cancelled.
+func (s *JobService) Start(ctx context.Context) {
+	if n, err := s.repo.FailUnfinishedJobs("interrupted by service restart"); err != nil {
+		logger.Log.Error("Failed to clean up unfinished jobs", zap.Error(err))
+	} else if n > 0 {
+		logger.Log.Warn("Marked unfinished jobs as failed", zap.Int64("count", n))
+	}
+
+	for i := 0; i < s.workers; i++ {
+		go s.work(ctx)
+	}
+	logger.Log.Info("Sync workers started", zap.Int("workers", s.workers))
+}
+
+// EnqueueSync records a queued job and hands a copy of it to the workers;
+// the returned job is a snapshot of the queued state, and GetJob reports
+// progress. A request naming a saved source is resolved to its stored
+// credentials first. It never blocks: when the queue is full the job is
+// marked failed and ErrQueueFull is returned.
+func (s *JobService) EnqueueSync(req domain.SyncRequest) (*domain.SyncJob, error) {
+	details, err := s.resolve(req)
+	if err != nil {
+		return nil, err
+	}
+	job, err := s.createJob(details)
+	if err != nil {
+		return nil, err
+	}
+
+	// The worker updates its own copy, so the job returned to the caller is
+	// never written concurrently.
+	queued := *job
+	select {
+	case s.queue <- queuedJob{job: &queued, details: details}:
+		logger.Log.Info("Sync job queued", zap.String("jobID", job.ID), zap.String("host", job.Host), zap.String("dbname", job.DBName))
+		return job, nil
+	default:
+		s.finish(job, ErrQueueFull)
+		return nil, ErrQueueFull
+	}
+}
+
+// resolve returns the connection details to sync for req, loading the
+// stored credentials of a saved source.
+func (s *JobService) resolve(req domain.SyncRequest) (domain.ConnectionDetails, error) {
+	if req.SourceID != "" {
+		return s.sources.ResolveConnection(req.SourceID)
+	}
+	return req.ConnectionDetails, nil
+}
+
+// createJob records a queued job for details.
+func (s *JobService) createJob(details domain.ConnectionDetails) (*domain.SyncJob, error) {
+	job := &domain.SyncJob{
+		ID:        uuid.NewString(),
+		Status:    domain.JobQueued,
+		Host:      details.Host,
+		DBName:    details.DBName,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.CreateJob(job); err != nil {
+		logger.Log.Error("CreateJob failed", zap.Error(err))
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *JobService) GetJob(id string) (*domain.SyncJob, error) {
+	job, err := s.repo.GetJobByID(id)
+	if err != nil {
+		logger.Log.Error("GetJob failed", zap.String("jobID", id), zap.Error(err))
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *JobService) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case queued := <-s.queue:
+			s.run(ctx, queued)
+		}
+	}
+}
+
+// run executes a queued job. ctx is the worker context, so shutting the
+// service down cancels in-flight fetches.
+func (s *JobService) run(ctx context.Context, queued queuedJob) {
+	job := queued.job
+	started := time.Now()
+	job.Status = domain.JobRunning
+	job.StartedAt = &started
+	s.save(job)
+
+	summary, err := s.summaries.updateSummary(ctx, queued.details, func(attempt int) {
+		job.Attempts = attempt
+		s.save(job)
+	})
+	if err == nil {
+		job.SummaryID = &summary.ID
+	}
+	s.finish(job, err)
+}
+
+func (s *JobService) finish(job *domain.SyncJob, err error) {
+	finished := time.Now()
+	job.FinishedAt = &finished
+	if err != nil {
+		job.Status = domain.JobFailed
+		job.Error = err.Error()
+		logger.Log.Warn("Sync job failed", zap.String("jobID", job.ID), zap.Int("attempts", job.Attempts), zap.Error(err))
+	} else {
+		job.Status = domain.JobSucceeded
+		logger.Log.Info("Sync job succeeded", zap.String("jobID", job.ID), zap.Int("attempts", job.Attempts))
+	}
+	s.save(job)
+}
+
+func (s *JobService) save(job *domain.SyncJob) {
+	if err := s.repo.UpdateJob(job); err != nil {
+		logger.Log.Error("UpdateJob failed", zap.String("jobID", job.ID), zap.String("status", job.Status), zap.Error(err))
+	}
+}
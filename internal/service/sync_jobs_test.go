@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockJobRepo struct {
+	mock.Mock
+	mu sync.Mutex
+	// statuses records every status a job was saved with, in order.
+	statuses []string
+}
+
+func (m *mockJobRepo) CreateJob(job *domain.SyncJob) error {
+	return m.Called(job).Error(0)
+}
+
+func (m *mockJobRepo) UpdateJob(job *domain.SyncJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.statuses = append(m.statuses, job.Status)
+	return nil
+}
+
+func (m *mockJobRepo) GetJobByID(id string) (*domain.SyncJob, error) {
+	args := m.Called(id)
+	if j := args.Get(0); j != nil {
+		return j.(*domain.SyncJob), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockJobRepo) FailUnfinishedJobs(reason string) (int64, error) {
+	args := m.Called(reason)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// savedAs mimics SaveSummary assigning the derived snapshot ID.
+func savedAs(id string) func(mock.Arguments) {
+	return func(args mock.Arguments) { args.Get(0).(*domain.Summary).ID = id }
+}
+
+func TestJobService_RunSucceeds(t *testing.T) {
+	repo := new(mockRepo)
+	client := new(mockExternalClient)
+	jobRepo := new(mockJobRepo)
+	jobs := NewJobService(jobRepo, NewSummaryService(repo, client, attempts(3)), nil, 1, 1)
+
+	details := domain.ConnectionDetails{Host: "localhost", DBName: "demo"}
+	client.On("FetchSummary", details).Return(domain.Summary{}, errors.New("timeout")).Once()
+	client.On("FetchSummary", details).Return(domain.Summary{ID: "sum-1"}, nil).Once()
+	repo.On("SaveSummary", mock.AnythingOfType("*domain.Summary")).Run(savedAs("sum-1")).Return(nil)
+	jobRepo.On("CreateJob", mock.AnythingOfType("*domain.SyncJob")).Return(nil)
+
+	job, err := jobs.EnqueueSync(domain.SyncRequest{ConnectionDetails: details})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.JobQueued, job.Status)
+
+	queued := <-jobs.queue
+	jobs.run(context.Background(), queued)
+
+	ran := queued.job
+	assert.Equal(t, job.ID, ran.ID)
+	assert.Equal(t, domain.JobSucceeded, ran.Status)
+	assert.Equal(t, 2, ran.Attempts)
+	assert.Equal(t, "sum-1", *ran.SummaryID)
+	assert.NotNil(t, ran.StartedAt)
+	assert.NotNil(t, ran.FinishedAt)
+	assert.Equal(t, domain.JobRunning, jobRepo.statuses[0])
+	assert.Equal(t, domain.JobQueued, job.Status, "the returned job is not updated by the worker")
+}
+
+func TestJobService_RunFails(t *testing.T) {
+	client := new(mockExternalClient)
+	jobRepo := new(mockJobRepo)
+	jobs := NewJobService(jobRepo, NewSummaryService(new(mockRepo), client, attempts(2)), nil, 1, 1)
+
+	details := domain.ConnectionDetails{Host: "badhost"}
+	client.On("FetchSummary", details).Return(domain.Summary{}, errors.New("fetch failed"))
+	jobRepo.On("CreateJob", mock.AnythingOfType("*domain.SyncJob")).Return(nil)
+
+	_, err := jobs.EnqueueSync(domain.SyncRequest{ConnectionDetails: details})
+	assert.NoError(t, err)
+
+	queued := <-jobs.queue
+	jobs.run(context.Background(), queued)
+
+	job := queued.job
+	assert.Equal(t, domain.JobFailed, job.Status)
+	assert.Equal(t, 2, job.Attempts)
+	assert.Equal(t, "fetch failed", job.Error)
+	assert.Nil(t, job.SummaryID)
+}
+
+// TestJobService_EnqueueWithLiveWorker reads the returned job while a
+// worker runs it; run with -race to catch shared writes.
+func TestJobService_EnqueueWithLiveWorker(t *testing.T) {
+	repo := new(mockRepo)
+	client := new(mockExternalClient)
+	jobRepo := new(mockJobRepo)
+	jobs := NewJobService(jobRepo, NewSummaryService(repo, client, attempts(1)), nil, 1, 1)
+
+	details := domain.ConnectionDetails{Host: "localhost", DBName: "demo"}
+	client.On("FetchSummary", details).Return(domain.Summary{}, nil)
+	repo.On("SaveSummary", mock.AnythingOfType("*domain.Summary")).Run(savedAs("sum-1")).Return(nil)
+	jobRepo.On("CreateJob", mock.AnythingOfType("*domain.SyncJob")).Return(nil)
+	jobRepo.On("FailUnfinishedJobs", mock.Anything).Return(int64(0), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	jobs.Start(ctx)
+
+	job, err := jobs.EnqueueSync(domain.SyncRequest{ConnectionDetails: details})
+	assert.NoError(t, err)
+	finished := func() bool {
+		jobRepo.mu.Lock()
+		defer jobRepo.mu.Unlock()
+		n := len(jobRepo.statuses)
+		return n > 0 && jobRepo.statuses[n-1] == domain.JobSucceeded
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !finished() {
+		if time.Now().After(deadline) {
+			t.Fatal("job did not finish")
+		}
+		_, err := json.Marshal(job)
+		assert.NoError(t, err)
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, domain.JobQueued, job.Status)
+}
+
+func TestJobService_EnqueueQueueFull(t *testing.T) {
+	jobRepo := new(mockJobRepo)
+	jobs := NewJobService(jobRepo, nil, nil, 1, 1)
+
+	jobRepo.On("CreateJob", mock.AnythingOfType("*domain.SyncJob")).Return(nil)
+
+	_, err := jobs.EnqueueSync(domain.SyncRequest{ConnectionDetails: domain.ConnectionDetails{Host: "a"}})
+	assert.NoError(t, err)
+
+	job, err := jobs.EnqueueSync(domain.SyncRequest{ConnectionDetails: domain.ConnectionDetails{Host: "b"}})
+	assert.Nil(t, job)
+	assert.ErrorIs(t, err, ErrQueueFull)
+	assert.Equal(t, []string{domain.JobFailed}, jobRepo.statuses)
+}
+
+func TestJobService_SyncBatch(t *testing.T) {
+	sourceRepo := new(mockSourceRepo)
+	jobRepo := new(mockJobRepo)
+	// No workers are started, so the queue of two fills up.
+	jobs := NewJobService(jobRepo, nil, NewSourceService(sourceRepo, nil), 1, 2)
+
+	sourceRepo.On("GetSourceByID", "s1").Return(&domain.Source{ID: "s1", Host: "db1", Port: 5432, User: "u", DBName: "demo"}, nil)
+	sourceRepo.On("GetSourceByID", "missing").Return(nil, nil)
+	jobRepo.On("CreateJob", mock.AnythingOfType("*domain.SyncJob")).Return(nil)
+
+	response, err := jobs.SyncBatch([]domain.SyncRequest{
+		{SourceID: "s1"},
+		{ConnectionDetails: domain.ConnectionDetails{Host: "db2", User: "u", DBName: "demo"}},
+		{SourceID: "missing"},
+		{ConnectionDetails: domain.ConnectionDetails{Host: "db3"}},
+		{ConnectionDetails: domain.ConnectionDetails{Host: "db4", User: "u", DBName: "demo"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, response.Queued)
+	assert.Equal(t, 3, response.Failed)
+
+	results := response.Results
+	assert.Equal(t, domain.JobQueued, results[0].Status)
+	assert.Equal(t, "db1", results[0].Host)
+	assert.NotEmpty(t, results[0].JobID)
+
+	assert.Equal(t, 1, results[1].Index)
+	assert.Equal(t, domain.JobQueued, results[1].Status)
+	assert.NotEmpty(t, results[1].JobID)
+
+	assert.Equal(t, domain.JobFailed, results[2].Status)
+	assert.Equal(t, ErrSourceNotFound.Error(), results[2].Error)
+	assert.Empty(t, results[2].JobID)
+	assert.Equal(t, ErrInvalidSyncRequest.Error(), results[3].Error)
+	assert.Equal(t, ErrQueueFull.Error(), results[4].Error)
+	assert.Len(t, jobs.queue, 2)
+	jobRepo.AssertNumberOfCalls(t, "CreateJob", 3)
+}
+
+func TestJobService_SyncBatchSize(t *testing.T) {
+	jobs := NewJobService(new(mockJobRepo), nil, nil, 1, 1)
+
+	_, err := jobs.SyncBatch(nil)
+	assert.ErrorIs(t, err, ErrInvalidBatch)
+	_, err = jobs.SyncBatch(make([]domain.SyncRequest, MaxBatchSize+1))
+	assert.ErrorIs(t, err, ErrInvalidBatch)
+}
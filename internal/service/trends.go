@@ -0,0 +1,244 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+	"github.com/lokesh2201013/postgres-data-summary/internal/logger"
+	"github.com/lokesh2201013/postgres-data-summary/internal/repository/local"
+)
+
+// DefaultTrendWindows are the growth windows, in days, reported when a
+// trend request names none.
+var DefaultTrendWindows = []int{7, 30, 90}
+
+const (
+	// MaxTrendWindows caps how many windows one trend request may ask for.
+	MaxTrendWindows = 5
+	// MaxTrendWindowDays caps the length of a single window.
+	MaxTrendWindowDays = 3650
+	// MaxForecastDays caps how far ahead a capacity forecast projects; a
+	// crossing further out is reported without DaysUntil or CrossesAt.
+	MaxForecastDays = 36500
+)
+
+type ITrendService interface {
+	GetSourceTrends(sourceID string, query domain.TrendQuery) (*domain.SourceTrends, error)
+}
+
+// TrendService turns the snapshot history of a source into growth rates
+// and capacity forecasts.
+type TrendService struct {
+	repo local.TrendRepository
+	now  func() time.Time
+}
+
+func NewTrendService(repo local.TrendRepository) *TrendService {
+	return &TrendService{repo: repo, now: time.Now}
+}
+
+// trendSample is the size of a database or table in one snapshot.
+type trendSample struct {
+	at       time.Time
+	rowCount int64
+	sizeMB   float64
+}
+
+type tableKey struct {
+	schema string
+	name   string
+}
+
+// GetSourceTrends reports the growth of a source database and each of its
+// current tables over every window in query, and forecasts when the
+// database reaches query.ThresholdMB if set.
+func (s *TrendService) GetSourceTrends(sourceID string, query domain.TrendQuery) (*domain.SourceTrends, error) {
+	windows, err := trendWindows(query)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	since := now.AddDate(0, 0, -windows[len(windows)-1])
+	points, err := s.repo.GetTrendPoints(sourceID, since)
+	if err != nil {
+		logger.Log.Error("Failed to get trend points", zap.String("sourceID", sourceID), zap.Error(err))
+		return nil, err
+	}
+	if len(points) == 0 {
+		return nil, ErrSourceNotFound
+	}
+
+	// Points arrive oldest first, grouped by snapshot.
+	var database []trendSample
+	tables := make(map[tableKey][]trendSample)
+	lastID := ""
+	for _, p := range points {
+		if p.SummaryID != lastID {
+			database = append(database, trendSample{at: p.SyncedAt})
+			lastID = p.SummaryID
+		}
+		if p.TableName == "" {
+			continue
+		}
+		snapshot := &database[len(database)-1]
+		snapshot.rowCount += p.RowCount
+		snapshot.sizeMB += p.SizeMB
+		key := tableKey{schema: p.SchemaName, name: p.TableName}
+		tables[key] = append(tables[key], trendSample{at: p.SyncedAt, rowCount: p.RowCount, sizeMB: p.SizeMB})
+	}
+
+	newest := points[len(points)-1]
+	current := database[len(database)-1]
+	trends := &domain.SourceTrends{
+		SourceID: sourceID,
+		Host:     newest.Host,
+		DBName:   newest.DBName,
+		SyncedAt: newest.SyncedAt,
+		Database: domain.DatabaseTrend{
+			RowCount: current.rowCount,
+			SizeMB:   current.sizeMB,
+			Growth:   growthOver(database, now, windows),
+		},
+		Tables: []domain.TableTrend{},
+	}
+	for key, samples := range tables {
+		last := samples[len(samples)-1]
+		if !last.at.Equal(newest.SyncedAt) {
+			// The table is gone from the newest snapshot.
+			continue
+		}
+		trends.Tables = append(trends.Tables, domain.TableTrend{
+			SchemaName: key.schema,
+			Name:       key.name,
+			RowCount:   last.rowCount,
+			SizeMB:     last.sizeMB,
+			Growth:     growthOver(samples, now, windows),
+		})
+	}
+	// Fastest growing tables over the longest window first.
+	sort.Slice(trends.Tables, func(i, j int) bool {
+		a, b := trends.Tables[i], trends.Tables[j]
+		rateA, rateB := a.Growth[len(a.Growth)-1].SizeMBPerDay, b.Growth[len(b.Growth)-1].SizeMBPerDay
+		if rateA != rateB {
+			return rateA > rateB
+		}
+		if a.SchemaName != b.SchemaName {
+			return a.SchemaName < b.SchemaName
+		}
+		return a.Name < b.Name
+	})
+
+	if query.ThresholdMB != nil {
+		trends.Forecast = forecast(current, trends.Database.Growth[len(windows)-1], *query.ThresholdMB)
+	}
+
+	logger.Log.Info("Computed source trends", zap.String("sourceID", sourceID), zap.Int("snapshots", len(database)), zap.Int("tables", len(trends.Tables)))
+	return trends, nil
+}
+
+// trendWindows validates the requested windows and returns them sorted
+// and deduplicated, falling back to DefaultTrendWindows.
+func trendWindows(query domain.TrendQuery) ([]int, error) {
+	if t := query.ThresholdMB; t != nil && (!(*t > 0) || math.IsInf(*t, 1)) {
+		return nil, fmt.Errorf("%w: thresholdMb must be a positive, finite number", ErrInvalidQuery)
+	}
+	if len(query.Windows) == 0 {
+		return DefaultTrendWindows, nil
+	}
+	if len(query.Windows) > MaxTrendWindows {
+		return nil, fmt.Errorf("%w: at most %d windows, got %d", ErrInvalidQuery, MaxTrendWindows, len(query.Windows))
+	}
+	windows := make([]int, 0, len(query.Windows))
+	seen := make(map[int]bool)
+	for _, days := range query.Windows {
+		if days < 1 || days > MaxTrendWindowDays {
+			return nil, fmt.Errorf("%w: windows must be between 1 and %d days, got %d", ErrInvalidQuery, MaxTrendWindowDays, days)
+		}
+		if !seen[days] {
+			seen[days] = true
+			windows = append(windows, days)
+		}
+	}
+	sort.Ints(windows)
+	return windows, nil
+}
+
+// growthOver measures samples, oldest first, over each window ending now.
+func growthOver(samples []trendSample, now time.Time, windows []int) []domain.Growth {
+	growth := make([]domain.Growth, 0, len(windows))
+	for _, days := range windows {
+		since := now.AddDate(0, 0, -days)
+		start := sort.Search(len(samples), func(i int) bool { return !samples[i].at.Before(since) })
+		in := samples[start:]
+
+		g := domain.Growth{WindowDays: days, Snapshots: len(in)}
+		if len(in) >= 2 {
+			first, last := in[0], in[len(in)-1]
+			g.RowDelta = last.rowCount - first.rowCount
+			g.SizeMBDelta = last.sizeMB - first.sizeMB
+			g.RowsPerDay = slopePerDay(in, func(s trendSample) float64 { return float64(s.rowCount) })
+			g.SizeMBPerDay = slopePerDay(in, func(s trendSample) float64 { return s.sizeMB })
+		}
+		growth = append(growth, g)
+	}
+	return growth
+}
+
+// slopePerDay fits a least-squares line through value over time and
+// returns its slope per day, or zero when all samples share a timestamp.
+func slopePerDay(samples []trendSample, value func(trendSample) float64) float64 {
+	origin := samples[0].at
+	day := func(s trendSample) float64 { return s.at.Sub(origin).Hours() / 24 }
+
+	var sumX, sumY float64
+	for _, s := range samples {
+		sumX += day(s)
+		sumY += value(s)
+	}
+	n := float64(len(samples))
+	meanX, meanY := sumX/n, sumY/n
+
+	var covariance, variance float64
+	for _, s := range samples {
+		dx := day(s) - meanX
+		covariance += dx * (value(s) - meanY)
+		variance += dx * dx
+	}
+	if variance == 0 {
+		return 0
+	}
+	return covariance / variance
+}
+
+// forecast projects when current grows past thresholdMB at the size rate
+// of growth, counting from the newest snapshot.
+func forecast(current trendSample, growth domain.Growth, thresholdMB float64) *domain.CapacityForecast {
+	f := &domain.CapacityForecast{
+		ThresholdMB:  thresholdMB,
+		WindowDays:   growth.WindowDays,
+		SizeMBPerDay: growth.SizeMBPerDay,
+	}
+	if current.sizeMB >= thresholdMB {
+		f.Reached = true
+		return f
+	}
+	if growth.SizeMBPerDay <= 0 {
+		return f
+	}
+	days := (thresholdMB - current.sizeMB) / growth.SizeMBPerDay
+	// A negligible rate puts the crossing beyond any useful horizon, or
+	// overflows to +Inf, which JSON cannot encode.
+	if math.IsInf(days, 0) || math.IsNaN(days) || days > MaxForecastDays {
+		return f
+	}
+	at := current.at.Add(time.Duration(days * 24 * float64(time.Hour)))
+	f.DaysUntil = &days
+	f.CrossesAt = &at
+	return f
+}
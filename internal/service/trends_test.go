@@ -0,0 +1,124 @@
+package service
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/lokesh2201013/postgres-data-summary/internal/domain"
+)
+
+type mockTrendRepo struct {
+	mock.Mock
+}
+
+func (m *mockTrendRepo) GetTrendPoints(sourceID string, since time.Time) ([]domain.TrendPoint, error) {
+	args := m.Called(sourceID, since)
+	if p := args.Get(0); p != nil {
+		return p.([]domain.TrendPoint), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestGetSourceTrends_GrowthAndForecast(t *testing.T) {
+	repo := new(mockTrendRepo)
+	trends := NewTrendService(repo)
+	now := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	trends.now = func() time.Time { return now }
+
+	day := func(ago int) time.Time { return now.AddDate(0, 0, -ago) }
+	point := func(id string, ago int, table string, rows int64, size float64) domain.TrendPoint {
+		return domain.TrendPoint{SummaryID: id, SyncedAt: day(ago), Host: "pg1", DBName: "billing",
+			SchemaName: "public", TableName: table, RowCount: rows, SizeMB: size}
+	}
+	points := []domain.TrendPoint{
+		point("s1", 20, "invoices", 100, 100),
+		point("s1", 20, "legacy", 5, 50),
+		point("s2", 10, "invoices", 200, 200),
+		point("s3", 0, "invoices", 300, 300),
+		point("s3", 0, "events", 10, 10),
+	}
+	repo.On("GetTrendPoints", "src-1", day(30)).Return(points, nil)
+
+	threshold := 1000.0
+	result, err := trends.GetSourceTrends("src-1", domain.TrendQuery{Windows: []int{30, 5, 30}, ThresholdMB: &threshold})
+	assert.NoError(t, err)
+	assert.Equal(t, "billing", result.DBName)
+	assert.Equal(t, day(0), result.SyncedAt)
+	assert.Equal(t, 310.0, result.Database.SizeMB)
+	assert.Equal(t, int64(310), result.Database.RowCount)
+
+	assert.Len(t, result.Database.Growth, 2)
+	short, long := result.Database.Growth[0], result.Database.Growth[1]
+	assert.Equal(t, 5, short.WindowDays)
+	assert.Equal(t, 1, short.Snapshots)
+	assert.Zero(t, short.SizeMBPerDay)
+	assert.Equal(t, 30, long.WindowDays)
+	assert.Equal(t, 3, long.Snapshots)
+	assert.InDelta(t, 160, long.SizeMBDelta, 1e-9)
+
+	// The legacy table was dropped before the newest snapshot.
+	assert.Len(t, result.Tables, 2)
+	assert.Equal(t, "invoices", result.Tables[0].Name)
+	assert.InDelta(t, 10, result.Tables[0].Growth[1].SizeMBPerDay, 1e-9)
+	assert.InDelta(t, 10, result.Tables[0].Growth[1].RowsPerDay, 1e-9)
+	assert.Equal(t, "events", result.Tables[1].Name)
+
+	assert.NotNil(t, result.Forecast)
+	assert.Equal(t, 30, result.Forecast.WindowDays)
+	assert.False(t, result.Forecast.Reached)
+	assert.NotNil(t, result.Forecast.CrossesAt)
+	assert.InDelta(t, (1000-310)/long.SizeMBPerDay, *result.Forecast.DaysUntil, 1e-9)
+}
+
+func TestGetSourceTrends_Errors(t *testing.T) {
+	repo := new(mockTrendRepo)
+	trends := NewTrendService(repo)
+	now := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	trends.now = func() time.Time { return now }
+
+	repo.On("GetTrendPoints", "missing", now.AddDate(0, 0, -90)).Return([]domain.TrendPoint{}, nil)
+
+	_, err := trends.GetSourceTrends("missing", domain.TrendQuery{})
+	assert.ErrorIs(t, err, ErrSourceNotFound)
+
+	_, err = trends.GetSourceTrends("src-1", domain.TrendQuery{Windows: []int{0}})
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+	_, err = trends.GetSourceTrends("src-1", domain.TrendQuery{Windows: []int{1, 2, 3, 4, 5, 6}})
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+	for _, threshold := range []float64{-1, math.Inf(1), math.NaN()} {
+		_, err = trends.GetSourceTrends("src-1", domain.TrendQuery{ThresholdMB: &threshold})
+		assert.ErrorIs(t, err, ErrInvalidQuery, "threshold %g", threshold)
+	}
+	repo.AssertNumberOfCalls(t, "GetTrendPoints", 1)
+}
+
+func TestForecast(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	current := trendSample{at: at, sizeMB: 500}
+
+	f := forecast(current, domain.Growth{WindowDays: 30, SizeMBPerDay: 50}, 1000)
+	assert.Equal(t, 10.0, *f.DaysUntil)
+	assert.Equal(t, at.AddDate(0, 0, 10), *f.CrossesAt)
+
+	f = forecast(current, domain.Growth{WindowDays: 30, SizeMBPerDay: -5}, 1000)
+	assert.False(t, f.Reached)
+	assert.Nil(t, f.CrossesAt)
+
+	f = forecast(current, domain.Growth{WindowDays: 30, SizeMBPerDay: 50}, 400)
+	assert.True(t, f.Reached)
+	assert.Nil(t, f.DaysUntil)
+
+	for _, rate := range []float64{1e-320, 1e-9} {
+		f = forecast(current, domain.Growth{WindowDays: 30, SizeMBPerDay: rate}, 1000)
+		assert.False(t, f.Reached)
+		assert.Nil(t, f.DaysUntil, "rate %g", rate)
+		assert.Nil(t, f.CrossesAt, "rate %g", rate)
+		_, err := json.Marshal(f)
+		assert.NoError(t, err)
+	}
+}